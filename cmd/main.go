@@ -11,6 +11,7 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"google.golang.org/grpc"
 
+	advisorModule "khazande/internal/advisor"
 	nvdModule "khazande/internal/nvd"
 	routerModule "khazande/internal/routers"
 	envsModule "khazande/pkg/envs"
@@ -20,7 +21,10 @@ import (
 )
 
 func main() {
-	app := fiber.New()
+	app := fiber.New(fiber.Config{
+		// Large enough for uploaded executables and source archives
+		BodyLimit: 256 * 1024 * 1024,
+	})
 	app.Use(logger.New())
 
 	envs := envsModule.ReadEnvs()
@@ -33,7 +37,12 @@ func main() {
 	}
 
 	grpcServer := grpc.NewServer()
-	pb.RegisterScrapperServiceServer(grpcServer, &nvdModule.Server{Logger: logger, RedisClient: redisClient})
+	pb.RegisterScrapperServiceServer(grpcServer, &nvdModule.Server{
+		Logger:      logger,
+		RedisClient: redisClient,
+		Envs:        envs,
+		Advisor:     &advisorModule.Advisor{Logger: logger, Envs: envs},
+	})
 
 	channel := make(chan os.Signal, 1)
 	signal.Notify(channel, os.Interrupt)
@@ -45,7 +54,7 @@ func main() {
 		}
 	}()
 
-	routers := routerModule.Initial(envs, logger)
+	routers := routerModule.Initial(envs, logger, redisClient)
 	routers.SetupRouters(app)
 
 	grpcServer.Stop()
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	vulndbModule "khazande/internal/vulndb"
+	envsModule "khazande/pkg/envs"
+	loggerModule "khazande/pkg/logger"
+)
+
+// vulndb-sync refreshes the local Go vulnerability database from a copy of vuln.go.dev, e.g.
+//
+//	vulndb-sync -from /mnt/transfer/vulndb.tar.gz
+func main() {
+	envs := envsModule.ReadEnvs()
+	logger := loggerModule.InitialLogger(envs.LOG_LEVEL)
+
+	from := flag.String("from", "", "directory or tarball (.tar, .tar.gz, .tgz) of the vuln.go.dev layout")
+	to := flag.String("to", envs.GO_VULNDB_PATH, "database to refresh, GO_VULNDB_PATH by default")
+	flag.Parse()
+
+	if *from == "" || *to == "" {
+		flag.Usage()
+		log.Fatalf("Both -from and -to (or GO_VULNDB_PATH) are required")
+	}
+
+	if current, err := vulndbModule.ReadDBIndex(*to); err == nil {
+		logger.Info(fmt.Sprintf("Current database of %s was modified %s", *to, current.Modified.Format(time.RFC3339)))
+	}
+
+	dbIndex, err := vulndbModule.Sync(*from, *to)
+	if err != nil {
+		log.Fatalf("Failed to sync the Go vulnerability database: %v", err)
+	}
+
+	logger.Info(fmt.Sprintf("Synced %s from %s, modified %s", *to, *from, dbIndex.Modified.Format(time.RFC3339)))
+}
@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"io"
 	types "khazande/internal/types"
+	versionsModule "khazande/internal/versions"
 	envsModule "khazande/pkg/envs"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 
-	"github.com/Masterminds/semver/v3"
 	"go.uber.org/zap"
 )
 
@@ -19,24 +21,73 @@ type Advisor struct {
 	Envs   *envsModule.Envs
 }
 
+// defaultMaxPages caps the number of securityVulnerabilities pages fetched per package
+const defaultMaxPages = 10
+
 type GitHubVulnerabilityQuery struct {
-	Query string `json:"query"`
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
 }
 
-func (a *Advisor) FetchVulnerabilitiesFromGithub(packages map[string]string) map[string][]*types.Vulnerability {
+// vulnerabilitiesQuery takes the package, ecosystem and cursor as variables, they are never
+// spliced into the query text
+const vulnerabilitiesQuery = `
+query($package: String!, $ecosystem: SecurityAdvisoryEcosystem!, $after: String) {
+	securityVulnerabilities(first: 100, package: $package, ecosystem: $ecosystem, after: $after) {
+		nodes {
+			package {
+				name
+			}
+			advisory {
+				ghsaId
+				permalink
+				summary
+				description
+				severity
+				identifiers {
+					type
+					value
+				}
+				references {
+					url
+				}
+				publishedAt
+				cvss {
+					score
+					vectorString
+				}
+			}
+			vulnerableVersionRange
+			firstPatchedVersion {
+				identifier
+			}
+			updatedAt
+		}
+		pageInfo {
+			endCursor
+			hasNextPage
+		}
+	}
+}`
+
+// FetchVulnerabilitiesFromGithub returns the vulnerabilities of the packages keyed by types.PackageKey,
+// packages of the same name in two ecosystems are kept apart
+func (a *Advisor) FetchVulnerabilitiesFromGithub(packages []types.Package) map[string][]*types.Vulnerability {
 	vulnerabilites := make(map[string][]*types.Vulnerability)
 	var wg sync.WaitGroup
 	var mutex sync.Mutex
 
-	for packageName, packageVersion := range packages {
+	for _, pkg := range packages {
 		wg.Add(1)
 
 		go func() {
 			defer wg.Done()
-			packageVulnerabilities := a.fetchVulnerabiltyOfSpecificPackage(packageName, packageVersion)
+			packageVulnerabilities := a.fetchVulnerabiltyOfSpecificPackage(pkg)
 
+			key := types.PackageKey(pkg.Ecosystem, pkg.Name)
 			mutex.Lock()
-			vulnerabilites[packageName] = packageVulnerabilities
+			// The same package may show up with several versions, e.g. in a package-lock.json
+			vulnerabilites[key] = append(vulnerabilites[key], packageVulnerabilities...)
 			mutex.Unlock()
 		}()
 	}
@@ -46,85 +97,43 @@ func (a *Advisor) FetchVulnerabilitiesFromGithub(packages map[string]string) map
 	return vulnerabilites
 }
 
-func (a *Advisor) fetchVulnerabiltyOfSpecificPackage(packageName string, version string) []*types.Vulnerability {
-	query := GitHubVulnerabilityQuery{
-		Query: fmt.Sprintf(`	
-		{
-			securityVulnerabilities(first: 100, package: "%s", ecosystem: GO) {
-				nodes {
-					package {
-						name
-					}
-					advisory {
-						summary
-						description
-						severity
-						identifiers {
-							type
-							value
-						}
-						publishedAt
-
-					}
-					vulnerableVersionRange
-					firstPatchedVersion {
-						identifier
-					}
-					updatedAt
-				}
-			}
-		}`, packageName),
-	}
-
-	jsonQuery, err := json.Marshal(query)
-	if err != nil {
-		a.Logger.Sugar().Errorf("Failed to marshal query: %v", err)
-		return nil
-	}
-
-	req, err := http.NewRequest("POST", a.Envs.GITHUB_ADVISORT_DATABASE_URL, bytes.NewBuffer(jsonQuery))
-	if err != nil {
-		a.Logger.Sugar().Errorf("Failed to create request: %v", err)
-		return nil
-	}
-
-	req.Header.Set("Authorization", "Bearer "+a.Envs.GITHUB_TOKEN)
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		a.Logger.Sugar().Errorf("Failed to perform request: %v", err)
-		return nil
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		a.Logger.Sugar().Errorf("Failed to read response body: %v", err)
-		return nil
+func (a *Advisor) fetchVulnerabiltyOfSpecificPackage(pkg types.Package) []*types.Vulnerability {
+	ecosystem := pkg.Ecosystem
+	if ecosystem == "" {
+		ecosystem = types.EcosystemGo
 	}
 
-	var githubResponse types.GitHubVulnerabilityQueryResponse
-
-	if err := json.Unmarshal(body, &githubResponse); err != nil {
-		a.Logger.Sugar().Errorf("Failed to unmarshal response: %v", err)
-		return nil
-	}
+	nodes := a.fetchVulnerabilityNodes(pkg.Name, ecosystem)
 
 	var result []*types.Vulnerability
 
-	for _, vulnerabilityNode := range githubResponse.Data.SecurityVulnerabilities.Nodes {
-		if vulnerabilityNode.Package.Name == packageName {
-			inRange, err := isVersionInRange(version, vulnerabilityNode.VulnerableVersionRange)
+	for _, vulnerabilityNode := range nodes {
+		if vulnerabilityNode.Package.Name == pkg.Name {
+			inRange, err := isVersionInRange(ecosystem, pkg.Version, vulnerabilityNode.VulnerableVersionRange)
 			if err != nil {
 				a.Logger.Sugar().Errorf("Error checking version range: %v", err)
 			}
 
-			if inRange {
+			// An unparsable version or range may hide a real vulnerability, it is reported as undetermined
+			if inRange || err != nil {
 				vulnerability := new(types.Vulnerability)
 
+				vulnerability.Match = types.MatchAffected
+				if err != nil {
+					vulnerability.Match = types.MatchUnknown
+					vulnerability.MatchReason = fmt.Sprintf("checking %s against %q failed: %v", pkg.Version, vulnerabilityNode.VulnerableVersionRange, err)
+				}
+
 				vulnerability.Name = vulnerabilityNode.Package.Name
+				vulnerability.Ecosystem = ecosystem
+				vulnerability.Version = pkg.Version
+				vulnerability.Indirect = pkg.Indirect
+				vulnerability.Line = pkg.Line
+				vulnerability.BomRef = pkg.BomRef
+				vulnerability.Layer = pkg.Layer
+				vulnerability.Location = pkg.Location
+				vulnerability.GHSAID = vulnerabilityNode.Advisory.GHSAID
+				vulnerability.Sources = []string{types.SourceGitHub}
 				vulnerability.Summary = vulnerabilityNode.Advisory.Summary
 				vulnerability.Description = vulnerabilityNode.Advisory.Description
 				vulnerability.Severity = vulnerabilityNode.Advisory.Severity
@@ -132,12 +141,27 @@ func (a *Advisor) fetchVulnerabiltyOfSpecificPackage(packageName string, version
 				vulnerability.LastModified = vulnerabilityNode.UpdatedAt.String()
 				vulnerability.AffectedVersions = vulnerabilityNode.VulnerableVersionRange
 				vulnerability.PatchedVersions = vulnerabilityNode.FirstPatchedVersion.Identifier
-				vulnerability.NVDScore = vulnerabilityNode.Advisory.CVSS.Score
+				if vulnerabilityNode.Advisory.CVSS.Score > 0 {
+					vulnerability.NVDScore = fmt.Sprintf("%.1f", vulnerabilityNode.Advisory.CVSS.Score)
+					vulnerability.NVDVector = vulnerabilityNode.Advisory.CVSS.VectorString
+				}
+
+				if vulnerabilityNode.Advisory.Permalink != "" {
+					vulnerability.References = append(vulnerability.References, vulnerabilityNode.Advisory.Permalink)
+				}
+				for _, reference := range vulnerabilityNode.Advisory.References {
+					if reference.URL != vulnerabilityNode.Advisory.Permalink {
+						vulnerability.References = append(vulnerability.References, reference.URL)
+					}
+				}
 
 				for _, identifier := range vulnerabilityNode.Advisory.Identifiers {
 					if identifier.Type == "CVE" {
 						vulnerability.CVEID = identifier.Value
 					}
+					if identifier.Value != vulnerability.GHSAID {
+						vulnerability.Aliases = append(vulnerability.Aliases, identifier.Value)
+					}
 				}
 
 				result = append(result, vulnerability)
@@ -148,19 +172,106 @@ func (a *Advisor) fetchVulnerabiltyOfSpecificPackage(packageName string, version
 	return result
 }
 
-func isVersionInRange(version string, versionRange string) (bool, error) {
-	// Parse the version from the go.mod
-	v, err := semver.NewVersion(version)
+// fetchVulnerabilityNodes follows the endCursor of securityVulnerabilities until every node of
+// the package is fetched or GITHUB_ADVISORY_MAX_PAGES pages have been requested
+func (a *Advisor) fetchVulnerabilityNodes(packageName string, ecosystem types.Ecosystem) []types.VulnerabilityNode {
+	maxPages := defaultMaxPages
+	if a.Envs.GITHUB_ADVISORY_MAX_PAGES != "" {
+		pages, err := strconv.Atoi(a.Envs.GITHUB_ADVISORY_MAX_PAGES)
+		if err != nil || pages < 1 {
+			a.Logger.Sugar().Errorf("Invalid GITHUB_ADVISORY_MAX_PAGES %q, falling back to %d", a.Envs.GITHUB_ADVISORY_MAX_PAGES, defaultMaxPages)
+		} else {
+			maxPages = pages
+		}
+	}
+
+	var nodes []types.VulnerabilityNode
+	cursor := ""
+
+	for page := 1; ; page++ {
+		// A failed page isn't the last one, what was fetched before it is kept but incomplete
+		githubResponse, err := a.queryGithub(packageName, ecosystem, cursor)
+		if err != nil {
+			a.Logger.Sugar().Errorf("Failed to fetch page %d of %s vulnerabilities, the results are incomplete: %v", page, packageName, err)
+			return nodes
+		}
+
+		nodes = append(nodes, githubResponse.Data.SecurityVulnerabilities.Nodes...)
+
+		pageInfo := githubResponse.Data.SecurityVulnerabilities.PageInfo
+		if !pageInfo.HasNextPage || pageInfo.EndCursor == "" {
+			return nodes
+		}
+
+		if page >= maxPages {
+			a.Logger.Sugar().Warnf("Stopped fetching %s vulnerabilities after %d pages, the results are incomplete", packageName, maxPages)
+			return nodes
+		}
+
+		cursor = pageInfo.EndCursor
+	}
+}
+
+func (a *Advisor) queryGithub(packageName string, ecosystem types.Ecosystem, cursor string) (*types.GitHubVulnerabilityQueryResponse, error) {
+	variables := map[string]interface{}{
+		"package":   packageName,
+		"ecosystem": string(ecosystem),
+		"after":     nil,
+	}
+	if cursor != "" {
+		variables["after"] = cursor
+	}
+
+	query := GitHubVulnerabilityQuery{Query: vulnerabilitiesQuery, Variables: variables}
+
+	jsonQuery, err := json.Marshal(query)
 	if err != nil {
-		return false, err
+		return nil, fmt.Errorf("failed to marshal query: %v", err)
 	}
 
-	// Parse the version range from the advisory data
-	constraint, err := semver.NewConstraint(versionRange)
+	req, err := http.NewRequest("POST", a.Envs.GITHUB_ADVISORT_DATABASE_URL, bytes.NewBuffer(jsonQuery))
 	if err != nil {
-		return false, err
+		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
 
-	// Check if the version satisfies the constraint
-	return constraint.Check(v), nil
+	req.Header.Set("Authorization", "Bearer "+a.Envs.GITHUB_TOKEN)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to perform request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("GitHub responded with %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var githubResponse types.GitHubVulnerabilityQueryResponse
+
+	if err := json.Unmarshal(body, &githubResponse); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+
+	// GraphQL reports failures such as rate limits with a 200 status
+	if len(githubResponse.Errors) != 0 {
+		messages := make([]string, 0, len(githubResponse.Errors))
+		for _, graphQLError := range githubResponse.Errors {
+			messages = append(messages, graphQLError.Message)
+		}
+		return nil, fmt.Errorf("GitHub query failed: %s", strings.Join(messages, "; "))
+	}
+
+	return &githubResponse, nil
+}
+
+func isVersionInRange(ecosystem types.Ecosystem, version string, versionRange string) (bool, error) {
+	// Compare the version from the manifest with the advisory range using the comparator of the package's ecosystem
+	return versionsModule.InRange(ecosystem, version, versionRange)
 }
@@ -0,0 +1,253 @@
+package aggregator
+
+import (
+	"fmt"
+	"strings"
+
+	advisorModule "khazande/internal/advisor"
+	distroModule "khazande/internal/distro"
+	osvModule "khazande/internal/osv"
+	"khazande/internal/types"
+	vulndbModule "khazande/internal/vulndb"
+	envsModule "khazande/pkg/envs"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Aggregator checks packages against several sources and merges what they report about the same
+// vulnerability into a single record
+type Aggregator struct {
+	Logger      *zap.Logger
+	Envs        *envsModule.Envs
+	RedisClient *redis.Client
+	Advisor     *advisorModule.Advisor
+	OSV         *osvModule.Client
+	VulnDB      *vulndbModule.Client
+	Distro      *distroModule.Client
+}
+
+// DefaultSources are used when the caller doesn't pick any
+var DefaultSources = []string{types.SourceGitHub}
+
+// packageSources match packages against their advisories, the other sources only add details to
+// the findings by CVE ID
+var packageSources = map[string]bool{
+	types.SourceGitHub: true,
+	types.SourceOSV:    true,
+}
+
+var knownSources = map[string]bool{
+	types.SourceGitHub: true,
+	types.SourceNVD:    true,
+	types.SourceOSV:    true,
+}
+
+// ParseSources reads a comma separated list of sources such as "github,osv,nvd"
+func ParseSources(list string) ([]string, error) {
+	if strings.TrimSpace(list) == "" {
+		return DefaultSources, nil
+	}
+
+	var sources []string
+	matchesPackages := false
+	for _, source := range strings.Split(list, ",") {
+		source = strings.ToLower(strings.TrimSpace(source))
+		if !knownSources[source] {
+			return nil, fmt.Errorf("unsupported source %q", source)
+		}
+		if hasSource(sources, source) {
+			continue
+		}
+		matchesPackages = matchesPackages || packageSources[source]
+		sources = append(sources, source)
+	}
+
+	if !matchesPackages {
+		return nil, fmt.Errorf("%s only adds details to findings, combine it with %s or %s", strings.Join(sources, ","), types.SourceGitHub, types.SourceOSV)
+	}
+
+	return sources, nil
+}
+
+// FetchVulnerabilities checks the packages against the sources and returns one merged record per
+// vulnerability and package version, keyed by types.PackageKey like Advisor.FetchVulnerabilitiesFromGithub.
+// When GO_VULNDB_PATH is set the Go modules are only checked against the local Go vulnerability database,
+// otherwise the Go standard library and toolchain are always checked against OSV. OS packages are checked
+// against the Alpine secdb and Debian security tracker data when configured, against OSV otherwise.
+func (a *Aggregator) FetchVulnerabilities(packages []types.Package, sources []string) map[string][]*types.Vulnerability {
+	vulnerabilities := make(map[string][]*types.Vulnerability)
+
+	if a.VulnDB.Enabled() {
+		var modules, others []types.Package
+		for _, pkg := range packages {
+			if pkg.Ecosystem == "" || pkg.Ecosystem == types.EcosystemGo {
+				modules = append(modules, pkg)
+			} else {
+				others = append(others, pkg)
+			}
+		}
+
+		for name, findings := range a.VulnDB.FetchVulnerabilities(modules) {
+			vulnerabilities[name] = append(vulnerabilities[name], findings...)
+		}
+		packages = others
+	}
+
+	if a.Distro.Enabled() {
+		var osPackages, others []types.Package
+		for _, pkg := range packages {
+			if a.Distro.Covers(pkg) {
+				osPackages = append(osPackages, pkg)
+			} else {
+				others = append(others, pkg)
+			}
+		}
+
+		for name, findings := range a.Distro.FetchVulnerabilities(osPackages) {
+			vulnerabilities[name] = append(vulnerabilities[name], findings...)
+		}
+		packages = others
+	}
+
+	// GitHub doesn't cover the Go standard library and toolchain nor OS packages, OSV does
+	var covered, uncovered []types.Package
+	for _, pkg := range packages {
+		if githubCovers(pkg) {
+			covered = append(covered, pkg)
+		} else {
+			uncovered = append(uncovered, pkg)
+		}
+	}
+
+	if hasSource(sources, types.SourceGitHub) {
+		for name, findings := range a.Advisor.FetchVulnerabilitiesFromGithub(covered) {
+			vulnerabilities[name] = append(vulnerabilities[name], findings...)
+		}
+	}
+
+	if !hasSource(sources, types.SourceOSV) {
+		packages = uncovered
+	}
+	if len(packages) != 0 {
+		for name, findings := range a.OSV.FetchVulnerabilities(packages) {
+			vulnerabilities[name] = append(vulnerabilities[name], findings...)
+		}
+	}
+
+	// Advisories of the go command are reported along with the standard library ones
+	toolchain, stdlib := types.PackageKey(types.EcosystemGo, types.GoToolchain), types.PackageKey(types.EcosystemGo, types.GoStdlib)
+	if findings, ok := vulnerabilities[toolchain]; ok {
+		vulnerabilities[stdlib] = append(vulnerabilities[stdlib], findings...)
+		delete(vulnerabilities, toolchain)
+	}
+
+	if hasSource(sources, types.SourceNVD) {
+		a.addNVDDetails(vulnerabilities)
+	}
+
+	for name, findings := range vulnerabilities {
+		vulnerabilities[name] = mergeFindings(findings)
+	}
+
+	return vulnerabilities
+}
+
+// addNVDDetails looks up the CVEs of the findings and adds the NVD record next to every finding
+// of the CVE, taking over its package
+func (a *Aggregator) addNVDDetails(vulnerabilities map[string][]*types.Vulnerability) {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, findings := range vulnerabilities {
+		for _, finding := range findings {
+			for _, id := range cveIDs(finding) {
+				if !seen[id] {
+					seen[id] = true
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+
+	records := a.LookupCVEs(ids)
+
+	for name, findings := range vulnerabilities {
+		for _, finding := range findings {
+			for _, id := range cveIDs(finding) {
+				record, ok := records[id]
+				if !ok {
+					continue
+				}
+
+				record.Name = finding.Name
+				record.Ecosystem = finding.Ecosystem
+				record.Version = finding.Version
+				record.Indirect = finding.Indirect
+				record.Line = finding.Line
+				record.BomRef = finding.BomRef
+				record.Layer = finding.Layer
+				record.Location = finding.Location
+				record.Match = finding.Match
+				record.MatchReason = finding.MatchReason
+				if len(record.Sources) == 0 {
+					// Cached before the records named their source
+					record.Sources = []string{types.SourceNVD}
+				}
+				vulnerabilities[name] = append(vulnerabilities[name], &record)
+			}
+		}
+	}
+}
+
+// githubCovers tells whether the GitHub Advisory Database has advisories for the package
+func githubCovers(pkg types.Package) bool {
+	switch pkg.Ecosystem {
+	case types.EcosystemAlpine, types.EcosystemDebian:
+		return false
+	case "", types.EcosystemGo:
+		return pkg.Name != types.GoStdlib && pkg.Name != types.GoToolchain
+	}
+	return true
+}
+
+// mergeFindings merges the findings of a package name version by version, the same advisory
+// affecting two versions stays two findings, as does a version found in two places or layers of
+// an image or in two components of an SBOM
+func mergeFindings(findings []*types.Vulnerability) []*types.Vulnerability {
+	var keys []string
+	groups := make(map[string][]*types.Vulnerability)
+	for _, finding := range findings {
+		key := strings.Join([]string{string(finding.Ecosystem), finding.Version, finding.Location, finding.Layer, finding.BomRef}, "@")
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], finding)
+	}
+
+	var merged []*types.Vulnerability
+	for _, key := range keys {
+		merged = append(merged, Merge(groups[key])...)
+	}
+
+	return merged
+}
+
+// cveIDs returns the CVE ID of the vulnerability along with the CVE aliases
+func cveIDs(vulnerability *types.Vulnerability) []string {
+	var ids []string
+	for _, id := range identifiers(vulnerability) {
+		if strings.HasPrefix(id, "CVE-") && !hasSource(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func hasSource(sources []string, source string) bool {
+	for _, s := range sources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,245 @@
+package aggregator
+
+import (
+	"sort"
+	"strings"
+
+	"khazande/internal/types"
+)
+
+// The sources are ranked per field, a field is taken from the best ranked record that has it and
+// sources missing from a ranking come last:
+//   - the package (name, ecosystem, version, line, bom-ref) comes from the first record that has a version
+//   - GHSAID, summary, description, severity, affected and patched versions: GitHub, the Go vulndb,
+//     the Alpine and Debian data, OSV, then NVD. The vulnerable imports come from the first of them
+//     listing any.
+//   - CVEID, NVD and CNA scores and vectors, vulnerable versions and CPE matches: NVD, GitHub, the
+//     Go vulndb, the Alpine and Debian data, then OSV
+//   - the match is undetermined only when no record could tell the version is affected
+//   - the published date is the earliest one and the last modified date the latest one
+//   - aliases, references and sources are the union of all records
+var (
+	advisoryPrecedence = []string{types.SourceGitHub, types.SourceVulnDB, types.SourceAlpine, types.SourceDebian, types.SourceOSV, types.SourceNVD}
+	nvdPrecedence      = []string{types.SourceNVD, types.SourceGitHub, types.SourceVulnDB, types.SourceAlpine, types.SourceDebian, types.SourceOSV}
+)
+
+// Merge correlates the records by their CVE ID, GHSA ID and aliases and merges the records of every
+// vulnerability into one. The merged records keep the order in which the vulnerabilities first show up.
+func Merge(records []*types.Vulnerability) []*types.Vulnerability {
+	// Union-find over the records, two records sharing an identifier are the same vulnerability
+	parents := make([]int, len(records))
+	for i := range parents {
+		parents[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parents[i] != i {
+			parents[i] = find(parents[i])
+		}
+		return parents[i]
+	}
+
+	owners := make(map[string]int)
+	for i, record := range records {
+		for _, id := range identifiers(record) {
+			owner, ok := owners[id]
+			if !ok {
+				owners[id] = i
+				continue
+			}
+			if a, b := find(owner), find(i); a != b {
+				parents[max(a, b)] = min(a, b)
+			}
+		}
+	}
+
+	var roots []int
+	groups := make(map[int][]*types.Vulnerability)
+	for i, record := range records {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], record)
+	}
+
+	merged := make([]*types.Vulnerability, 0, len(roots))
+	for _, root := range roots {
+		merged = append(merged, mergeGroup(groups[root]))
+	}
+
+	return merged
+}
+
+func mergeGroup(group []*types.Vulnerability) *types.Vulnerability {
+	if len(group) == 1 {
+		return group[0]
+	}
+
+	advisory := byPrecedence(group, advisoryPrecedence)
+	nvd := byPrecedence(group, nvdPrecedence)
+
+	merged := &types.Vulnerability{}
+
+	pkg := group[0]
+	for _, record := range group {
+		if record.Version != "" {
+			pkg = record
+			break
+		}
+	}
+	merged.Name = pkg.Name
+	merged.Ecosystem = pkg.Ecosystem
+	merged.Version = pkg.Version
+	merged.Indirect = pkg.Indirect
+	merged.Line = pkg.Line
+	merged.BomRef = pkg.BomRef
+	merged.Layer = pkg.Layer
+	merged.Location = pkg.Location
+
+	merged.GHSAID = first(advisory, func(v *types.Vulnerability) string { return v.GHSAID })
+	merged.Summary = first(advisory, func(v *types.Vulnerability) string { return v.Summary })
+	merged.Description = first(advisory, func(v *types.Vulnerability) string { return v.Description })
+	merged.Severity = first(advisory, func(v *types.Vulnerability) string { return v.Severity })
+	merged.AffectedVersions = first(advisory, func(v *types.Vulnerability) string { return v.AffectedVersions })
+	merged.PatchedVersions = first(advisory, func(v *types.Vulnerability) string { return v.PatchedVersions })
+
+	for _, record := range advisory {
+		if len(record.Imports) != 0 {
+			merged.Imports = record.Imports
+			break
+		}
+	}
+
+	merged.CVEID = first(nvd, func(v *types.Vulnerability) string { return v.CVEID })
+	// Score and vector go together, GitHub's CVSS is only a fallback for the NVD one
+	for _, record := range nvd {
+		if record.NVDScore != "" || record.NVDVector != "" {
+			merged.NVDScore, merged.NVDVector = record.NVDScore, record.NVDVector
+			break
+		}
+	}
+	for _, record := range nvd {
+		if record.CNAScore != "" {
+			merged.CNAScore, merged.CNAVector = record.CNAScore, record.CNAVector
+			break
+		}
+	}
+	for _, record := range nvd {
+		if len(record.CPEMatches) != 0 || len(record.VulnerableVersions) != 0 {
+			merged.CPEMatches, merged.VulnerableVersions = record.CPEMatches, record.VulnerableVersions
+			break
+		}
+	}
+
+	merged.Match = types.MatchUnknown
+	for _, record := range group {
+		if record.Match != types.MatchUnknown {
+			merged.Match = record.Match
+			break
+		}
+		if merged.MatchReason == "" {
+			merged.MatchReason = record.MatchReason
+		}
+	}
+	if merged.Match != types.MatchUnknown {
+		merged.MatchReason = ""
+	}
+
+	merged.PublishedDate = pickDate(group, func(v *types.Vulnerability) string { return v.PublishedDate }, -1)
+	merged.LastModified = pickDate(group, func(v *types.Vulnerability) string { return v.LastModified }, 1)
+
+	for _, record := range group {
+		for _, id := range identifiers(record) {
+			if id != strings.ToUpper(merged.GHSAID) && !containsFold(merged.Aliases, id) {
+				merged.Aliases = append(merged.Aliases, id)
+			}
+		}
+		for _, reference := range record.References {
+			if !containsFold(merged.References, reference) {
+				merged.References = append(merged.References, reference)
+			}
+		}
+		for _, source := range record.Sources {
+			if !containsFold(merged.Sources, source) {
+				merged.Sources = append(merged.Sources, source)
+			}
+		}
+	}
+
+	return merged
+}
+
+// identifiers are the upper cased CVE ID, GHSA ID and aliases of a record
+func identifiers(record *types.Vulnerability) []string {
+	var ids []string
+	for _, id := range append([]string{record.CVEID, record.GHSAID}, record.Aliases...) {
+		id = strings.ToUpper(strings.TrimSpace(id))
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// byPrecedence orders the records by the best ranked of their sources
+func byPrecedence(records []*types.Vulnerability, precedence []string) []*types.Vulnerability {
+	rank := func(record *types.Vulnerability) int {
+		best := len(precedence)
+		for _, source := range record.Sources {
+			for i, ranked := range precedence {
+				if source == ranked && i < best {
+					best = i
+				}
+			}
+		}
+		return best
+	}
+
+	sorted := append([]*types.Vulnerability(nil), records...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return rank(sorted[i]) < rank(sorted[j])
+	})
+
+	return sorted
+}
+
+func first(records []*types.Vulnerability, field func(*types.Vulnerability) string) string {
+	for _, record := range records {
+		if value := field(record); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// pickDate returns the earliest date for a direction of -1 and the latest one for 1. Unparsable
+// dates are only used when no date parses.
+func pickDate(records []*types.Vulnerability, field func(*types.Vulnerability) string, direction int) string {
+	picked := ""
+	var pickedTime int64
+	for _, record := range records {
+		date := field(record)
+		parsed, err := types.ParseDate(date)
+		if err != nil {
+			continue
+		}
+		if picked == "" || (direction < 0 && parsed.Unix() < pickedTime) || (direction > 0 && parsed.Unix() > pickedTime) {
+			picked, pickedTime = date, parsed.Unix()
+		}
+	}
+
+	if picked == "" {
+		return first(records, field)
+	}
+	return picked
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	crawlerModule "khazande/internal/crawler"
+	nvdapiModule "khazande/internal/nvdapi"
+	"khazande/internal/types"
+)
+
+// maxLookupWorkers bounds the concurrent NVD requests of LookupCVEs
+const maxLookupWorkers = 5
+
+// LookupCVEs returns the NVD records of the CVEs keyed by CVE ID. The cached ones are served from
+// Redis, the others are fetched concurrently. Unknown CVEs are left out.
+func (a *Aggregator) LookupCVEs(ids []string) map[string]types.Vulnerability {
+	found := make(map[string]types.Vulnerability)
+	var misses []string
+	for _, id := range ids {
+		if !types.IsCVEID(id) {
+			a.Logger.Sugar().Errorf("Skipping %q, it is not a CVE ID", id)
+			continue
+		}
+		if vulnerability, ok := a.cachedVulnerability(id); ok {
+			found[id] = vulnerability
+		} else {
+			misses = append(misses, id)
+		}
+	}
+	a.Logger.Info(fmt.Sprintf("Looking up %d CVEs, %d of them are cached", len(ids), len(ids)-len(misses)))
+
+	for id, vulnerability := range a.fetchCVEs(misses) {
+		found[id] = vulnerability
+	}
+
+	return found
+}
+
+// cachedVulnerability reads a vulnerability cached by the API client or the crawler, both key them by CVE ID
+func (a *Aggregator) cachedVulnerability(id string) (types.Vulnerability, bool) {
+	var vulnerability types.Vulnerability
+	if a.RedisClient == nil {
+		return vulnerability, false
+	}
+
+	val, err := a.RedisClient.Get(context.Background(), id).Result()
+	if err != nil {
+		return vulnerability, false
+	}
+
+	if err := json.Unmarshal([]byte(val), &vulnerability); err != nil || vulnerability.CVEID == "" {
+		return vulnerability, false
+	}
+
+	return vulnerability, true
+}
+
+// fetchCVEs fetches the CVEs from the NVD CVE API and scrapes the detail pages of the ones the API
+// failed to return. The workers share the rate limit of the API client.
+func (a *Aggregator) fetchCVEs(ids []string) map[string]types.Vulnerability {
+	found := make(map[string]types.Vulnerability)
+	if len(ids) == 0 {
+		return found
+	}
+
+	client := nvdapiModule.Client{Logger: a.Logger, Envs: a.Envs, RedisClient: a.RedisClient}
+
+	var mu sync.Mutex
+	var failed []string
+	var wg sync.WaitGroup
+	jobs := make(chan string)
+
+	for i := 0; i < min(maxLookupWorkers, len(ids)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range jobs {
+				vulnerability, err := client.GetVulnerability(id)
+
+				mu.Lock()
+				if err != nil {
+					a.Logger.Sugar().Errorf("NVD API failed for %s, falling back to the web crawler: %v", id, err)
+					failed = append(failed, id)
+				} else {
+					found[id] = vulnerability
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, id := range ids {
+		jobs <- id
+	}
+	close(jobs)
+	wg.Wait()
+
+	if len(failed) == 0 {
+		return found
+	}
+
+	crawler := crawlerModule.Crawler{Logger: a.Logger, RedisClient: a.RedisClient}
+
+	links := make([]string, 0, len(failed))
+	for _, id := range failed {
+		links = append(links, "https://nvd.nist.gov/vuln/detail/"+id)
+	}
+
+	for _, vulnerability := range crawler.ExtractVulnerabilitiesDetails("", links) {
+		// The detail page of an unknown CVE has nothing to extract
+		if vulnerability.CVEID != "" {
+			found[strings.ToUpper(vulnerability.CVEID)] = vulnerability
+		}
+	}
+
+	return found
+}
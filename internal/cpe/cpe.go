@@ -0,0 +1,212 @@
+package cpe
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"khazande/internal/types"
+	versionsModule "khazande/internal/versions"
+)
+
+var (
+	criteriaPattern = regexp.MustCompile(`(\*?)(cpe:2\.3:(?:\\.|[^\s])+)`)
+	startPattern    = regexp.MustCompile(`from \((including|excluding)\)\s+(\S+)`)
+	endPattern      = regexp.MustCompile(`up to \((including|excluding)\)\s+(\S+)`)
+	// The major version suffix of a Go module path, e.g. v2
+	majorVersionPattern = regexp.MustCompile(`^v[0-9]+$`)
+)
+
+// ParseCriteria splits a CPE 2.3 formatted string such as cpe:2.3:a:golang:go:1.20.1:*:*:*:*:*:*:*
+// into its vendor, product and version
+func ParseCriteria(criteria string) (vendor string, product string, version string, err error) {
+	var fields []string
+	var current strings.Builder
+	escaped := false
+
+	for _, r := range criteria {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ':':
+			fields = append(fields, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	fields = append(fields, current.String())
+
+	if len(fields) < 6 || fields[0] != "cpe" || fields[1] != "2.3" {
+		return "", "", "", fmt.Errorf("invalid CPE 2.3 string %q", criteria)
+	}
+
+	return fields[3], fields[4], fields[5], nil
+}
+
+// FromNVD converts a cpeMatch of the NVD CVE API
+func FromNVD(match types.NVDCPEMatch) types.CPEMatch {
+	record := types.CPEMatch{
+		Criteria:              match.Criteria,
+		VersionStartIncluding: match.VersionStartIncluding,
+		VersionStartExcluding: match.VersionStartExcluding,
+		VersionEndIncluding:   match.VersionEndIncluding,
+		VersionEndExcluding:   match.VersionEndExcluding,
+		Vulnerable:            match.Vulnerable,
+	}
+	record.Vendor, record.Product, record.Version, _ = ParseCriteria(match.Criteria)
+
+	return record
+}
+
+// ParseConfigurationText reads the CPE configurations the NVD detail page prints in its change
+// history, e.g. "*cpe:2.3:a:gin-gonic:gin:*:*:*:*:*:go:*:* versions up to (excluding) 1.9.1".
+// A leading * marks a vulnerable CPE.
+func ParseConfigurationText(text string) []types.CPEMatch {
+	var matches []types.CPEMatch
+	locations := criteriaPattern.FindAllStringSubmatchIndex(text, -1)
+
+	for i, location := range locations {
+		// The version range of a CPE is written between it and the next one
+		end := len(text)
+		if i+1 < len(locations) {
+			end = locations[i+1][0]
+		}
+		segment := text[location[1]:end]
+
+		match := types.CPEMatch{
+			Criteria:   text[location[4]:location[5]],
+			Vulnerable: location[3] > location[2],
+		}
+		var err error
+		match.Vendor, match.Product, match.Version, err = ParseCriteria(match.Criteria)
+		if err != nil {
+			continue
+		}
+
+		if start := startPattern.FindStringSubmatch(segment); start != nil {
+			if start[1] == "including" {
+				match.VersionStartIncluding = start[2]
+			} else {
+				match.VersionStartExcluding = start[2]
+			}
+		}
+		if end := endPattern.FindStringSubmatch(segment); end != nil {
+			if end[1] == "including" {
+				match.VersionEndIncluding = end[2]
+			} else {
+				match.VersionEndExcluding = end[2]
+			}
+		}
+
+		matches = append(matches, match)
+	}
+
+	return matches
+}
+
+// Describe renders a CPE match the way the NVD detail page shows it,
+// e.g. "cpe:2.3:a:golang:go:*:*:*:*:*:*:*:* from (including) 1.20.0 up to (excluding) 1.20.5"
+func Describe(match types.CPEMatch) string {
+	parts := []string{match.Criteria}
+	if match.VersionStartIncluding != "" {
+		parts = append(parts, "from (including) "+match.VersionStartIncluding)
+	}
+	if match.VersionStartExcluding != "" {
+		parts = append(parts, "from (excluding) "+match.VersionStartExcluding)
+	}
+	if match.VersionEndIncluding != "" {
+		parts = append(parts, "up to (including) "+match.VersionEndIncluding)
+	}
+	if match.VersionEndExcluding != "" {
+		parts = append(parts, "up to (excluding) "+match.VersionEndExcluding)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Contains reports whether a concrete version falls in the range of the CPE match. A CPE naming a
+// single version only contains that version, a wildcard without bounds contains every version.
+// Versions are compared with the rules of the ecosystem when both parse in it, NVD ranges are vendor
+// versions and don't always do.
+func Contains(match types.CPEMatch, ecosystem types.Ecosystem, version string) bool {
+	compare := func(a, b string) int {
+		result, err := versionsModule.Compare(ecosystem, a, b)
+		if err != nil {
+			result, _ = versionsModule.Compare("", a, b)
+		}
+		return result
+	}
+
+	if match.Version != "*" && match.Version != "" && match.Version != "-" {
+		return compare(version, match.Version) == 0
+	}
+
+	if match.VersionStartIncluding != "" && compare(version, match.VersionStartIncluding) < 0 {
+		return false
+	}
+	if match.VersionStartExcluding != "" && compare(version, match.VersionStartExcluding) <= 0 {
+		return false
+	}
+	if match.VersionEndIncluding != "" && compare(version, match.VersionEndIncluding) > 0 {
+		return false
+	}
+	if match.VersionEndExcluding != "" && compare(version, match.VersionEndExcluding) >= 0 {
+		return false
+	}
+
+	return true
+}
+
+// NormalizeProduct reduces a CPE product or a package name to a comparable form: lowercase, the last
+// element of a module path, Maven coordinate or npm scoped name, without the major version suffix of Go
+// modules and without separators, so github.com/jackc/pgx/v5 is pgx and node_fetch is nodefetch
+func NormalizeProduct(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	elements := strings.FieldsFunc(name, func(r rune) bool { return r == '/' || r == ':' })
+	// github.com/jackc/pgx/v5 is the pgx product
+	for len(elements) > 1 && majorVersionPattern.MatchString(elements[len(elements)-1]) {
+		elements = elements[:len(elements)-1]
+	}
+	if len(elements) != 0 {
+		name = elements[len(elements)-1]
+	}
+
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || r == '.' || r == '@' {
+			return -1
+		}
+		return r
+	}, name)
+}
+
+// Affects reports whether any vulnerable CPE match of the package contains the version. CPEs of
+// another product, and platforms the configuration only requires to be present, are skipped. The
+// package may be a keyword search such as "apache log4j", any of its words can be the product. When
+// name or vendor is empty any product or vendor is considered.
+func Affects(matches []types.CPEMatch, name string, vendor string, ecosystem types.Ecosystem, version string) bool {
+	products := make(map[string]bool)
+	for _, word := range append(strings.Fields(name), name) {
+		if product := NormalizeProduct(word); product != "" {
+			products[product] = true
+		}
+	}
+
+	for _, match := range matches {
+		if !match.Vulnerable {
+			continue
+		}
+		if len(products) != 0 && !products[NormalizeProduct(match.Product)] {
+			continue
+		}
+		if vendor != "" && !strings.EqualFold(match.Vendor, vendor) {
+			continue
+		}
+		if Contains(match, ecosystem, version) {
+			return true
+		}
+	}
+	return false
+}
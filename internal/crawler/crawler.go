@@ -4,12 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"strings"
 	"sync"
 	"time"
 
+	cpeModule "khazande/internal/cpe"
 	"khazande/internal/types"
 
 	"github.com/PuerkitoBio/goquery"
@@ -18,12 +18,19 @@ import (
 	"go.uber.org/zap"
 )
 
+// Crawler scrapes the NVD website, it is the fallback for when the NVD CVE API is unavailable
 type Crawler struct {
 	Logger      *zap.Logger
 	RedisClient *redis.Client
 }
 
 func (crawler *Crawler) ExtractVulnerabilitiesLinks(query string) []string {
+	return crawler.ExtractVulnerabilitiesLinksWithProgress(query, nil)
+}
+
+// ExtractVulnerabilitiesLinksWithProgress calls onPage, when given, after every result page with
+// the number of pages and links found so far
+func (crawler *Crawler) ExtractVulnerabilitiesLinksWithProgress(query string, onPage func(pages int, links int)) []string {
 	vulnerabilitiesLinks := []string{}
 	baseLink := generateLink(query)
 	counter := 0
@@ -35,6 +42,9 @@ func (crawler *Crawler) ExtractVulnerabilitiesLinks(query string) []string {
 		if len(vuls) != 0 {
 			vulnerabilitiesLinks = append(vulnerabilitiesLinks, vuls...)
 			counter += 20
+			if onPage != nil {
+				onPage(counter/20, len(vulnerabilitiesLinks))
+			}
 		} else {
 			break
 		}
@@ -59,43 +69,75 @@ func (crawler *Crawler) ExtractVulnerabilityLinksPerPage(link, query string) []s
 	return vulnerabiliyLinks
 }
 
+// ExtractVulnerabilitiesDetails scrapes every detail page, the pages that fail to load are logged
+// and left out
 func (crawler *Crawler) ExtractVulnerabilitiesDetails(query string, vulnerabilitiesLinks []string) []types.Vulnerability {
+	var vulnerSlice []types.Vulnerability
+	for result := range crawler.StreamVulnerabilitiesDetails(context.Background(), query, vulnerabilitiesLinks) {
+		if result.Err != nil {
+			continue
+		}
+		vulnerSlice = append(vulnerSlice, result.Vulnerability)
+	}
+
+	return vulnerSlice
+}
+
+// DetailResult is the outcome of scraping a detail page, Err is set when the page failed to load
+type DetailResult struct {
+	Link          string
+	Vulnerability types.Vulnerability
+	Err           error
+}
+
+// StreamVulnerabilitiesDetails scrapes the detail pages concurrently and sends the result of every
+// page as soon as it is scraped, failed pages are logged and sent with their error. No page is
+// started once ctx is done. The channel is closed once every started page is done.
+func (crawler *Crawler) StreamVulnerabilitiesDetails(ctx context.Context, query string, vulnerabilitiesLinks []string) <-chan DetailResult {
 	crawler.Logger.Info(fmt.Sprintf("Web Scrapper is started to extract data of %d vulnerabilities", len(vulnerabilitiesLinks)))
 
-	// Create a channel to handle the results
-	results := make(chan types.Vulnerability, len(vulnerabilitiesLinks))
-	// Create a WaitGroup to wait for all goroutines to finish
-	var wg sync.WaitGroup
-	concurrentWorkers := 10
-	sem := make(chan struct{}, concurrentWorkers)
-
-	for _, link := range vulnerabilitiesLinks {
-		wg.Add(1)
-		sem <- struct{}{}
-
-		go func(link string) {
-			defer wg.Done()
-			defer func() { <-sem }()
-			vuln := crawler.scrapeVulnerabilityDetails(query, link)
-			results <- vuln
-		}(link)
-	}
+	// Create a channel to handle the results, it is large enough for the workers to never block on it
+	results := make(chan DetailResult, len(vulnerabilitiesLinks))
 
 	go func() {
+		// Create a WaitGroup to wait for all goroutines to finish
+		var wg sync.WaitGroup
+		concurrentWorkers := 10
+		sem := make(chan struct{}, concurrentWorkers)
+
+	links:
+		for _, link := range vulnerabilitiesLinks {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				crawler.Logger.Info(fmt.Sprintf("Web Scrapper is stopped for %s: %v", query, ctx.Err()))
+				break links
+			}
+			wg.Add(1)
+
+			go func(link string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				vuln, err := crawler.scrapeVulnerabilityDetails(query, link)
+				if err != nil {
+					crawler.Logger.Sugar().Errorf("Failed to scrape %s: %v", link, err)
+				}
+				results <- DetailResult{Link: link, Vulnerability: vuln, Err: err}
+			}(link)
+		}
+
 		wg.Wait()
 		close(results)
 	}()
 
-	var vulnerSlice []types.Vulnerability
-	for vuln := range results {
-		vulnerSlice = append(vulnerSlice, vuln)
-	}
-
-	return vulnerSlice
+	return results
 }
 
-func (crawler *Crawler) scrapeVulnerabilityDetails(query, link string) types.Vulnerability {
+// scrapeVulnerabilityDetails extracts the vulnerability of a detail page, nothing is cached when the
+// page or its change history fails to load
+func (crawler *Crawler) scrapeVulnerabilityDetails(query, link string) (types.Vulnerability, error) {
 	var vuln types.Vulnerability
+	var scrapeErr error
 
 	splitedLink := strings.Split(link, "/")
 	val, err := crawler.RedisClient.Get(context.Background(), splitedLink[len(splitedLink)-1]).Result()
@@ -104,7 +146,7 @@ func (crawler *Crawler) scrapeVulnerabilityDetails(query, link string) types.Vul
 		crawler.Logger.Info(fmt.Sprintf("Cache miss for %s - %s", query, splitedLink[len(splitedLink)-1]))
 	} else {
 		json.Unmarshal([]byte(val), &vuln)
-		return vuln
+		return vuln, nil
 	}
 
 	c := colly.NewCollector()
@@ -141,31 +183,52 @@ func (crawler *Crawler) scrapeVulnerabilityDetails(query, link string) types.Vul
 	c.OnScraped(func(r *colly.Response) {
 		res, err := http.Get(link)
 		if err != nil {
-			log.Fatal(err)
+			scrapeErr = err
+			return
 		}
 		defer res.Body.Close()
-		if res.StatusCode != 200 {
-			log.Fatalf("status code error: %d %s", res.StatusCode, res.Status)
+		if res.StatusCode != http.StatusOK {
+			scrapeErr = fmt.Errorf("status code error: %d %s", res.StatusCode, res.Status)
+			return
 		}
 
 		doc, err := goquery.NewDocumentFromReader(res.Body)
 		if err != nil {
-			fmt.Println("Error creating GoQuery document:", err)
+			scrapeErr = fmt.Errorf("failed to parse the change history: %v", err)
 			return
 		}
 
 		var result []string
+		var matches []types.CPEMatch
+		seen := make(map[types.CPEMatch]bool)
 		doc.Find("td[data-testid*='vuln-change-history']").Each(func(i int, e *goquery.Selection) {
-			// if it's a td tag that includes a portion of the vulnerable versions
-			if strings.Contains(e.Text(), "*cpe") {
-				extractVulnerableVersions(e.Text(), &result)
+			// if it's a td tag that includes a portion of the CPE configurations
+			if strings.Contains(e.Text(), "cpe:2.3") {
+				// The same configuration shows up in several entries of the change history
+				for _, match := range cpeModule.ParseConfigurationText(e.Text()) {
+					if seen[match] {
+						continue
+					}
+					seen[match] = true
+					matches = append(matches, match)
+					if match.Vulnerable {
+						result = append(result, cpeModule.Describe(match))
+					}
+				}
 			}
 		})
 		vuln.Name = query
+		vuln.Sources = []string{types.SourceNVD}
 		vuln.VulnerableVersions = result
+		vuln.CPEMatches = matches
 	})
 
-	c.Visit(link)
+	if err := c.Visit(link); err != nil {
+		return types.Vulnerability{}, err
+	}
+	if scrapeErr != nil {
+		return types.Vulnerability{}, scrapeErr
+	}
 
 	jsonVulnerability, marshalErr := json.Marshal(vuln)
 	if marshalErr == nil {
@@ -173,7 +236,7 @@ func (crawler *Crawler) scrapeVulnerabilityDetails(query, link string) types.Vul
 		// crawler.Logger.Info(fmt.Sprintf("Cache set for %s - %s", query, splitedLink[len(splitedLink)-1]))
 	}
 
-	return vuln
+	return vuln, nil
 }
 
 func generateLink(query string) string {
@@ -191,35 +254,3 @@ func generateLink(query string) string {
 		queryType,
 		searchType)
 }
-
-func splitBeforeSeparator(input, separator string) []string {
-	var result []string
-	parts := strings.Split(input, separator)
-
-	for i, part := range parts {
-		if i > 0 {
-			part = separator + part
-		}
-		result = append(result, part)
-	}
-
-	return result
-}
-
-func extractVulnerableVersions(elementText string, result *[]string) {
-	strSlice := splitBeforeSeparator(elementText, "*cpe")
-	for index, str := range strSlice {
-		if strings.HasPrefix(str, "*cpe") {
-			if strings.Contains(str, "versions") {
-				arr := splitBeforeSeparator(str, "versions")
-				str = strings.TrimSpace(arr[1])
-				if index != len(strSlice)-1 {
-					*result = append(*result, str)
-				} else {
-					s := strings.Split(str, "\n")
-					*result = append(*result, string(s[0]))
-				}
-			}
-		}
-	}
-}
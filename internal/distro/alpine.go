@@ -0,0 +1,91 @@
+package distro
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"khazande/internal/types"
+)
+
+// alpineSecDB is a file of https://secdb.alpinelinux.org, e.g. v3.18/main.json
+type alpineSecDB struct {
+	DistroVersion string `json:"distroversion"`
+	Packages      []struct {
+		Pkg struct {
+			Name string `json:"name"`
+			// Fixed version to the IDs it fixes, "0" lists the IDs that never affected the release
+			SecFixes map[string][]string `json:"secfixes"`
+		} `json:"pkg"`
+	} `json:"packages"`
+}
+
+// AlpineRelease names the release of an Alpine version the way the secdb does, v3.18 for 3.18.4
+func AlpineRelease(version string) string {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if parts[0] == "" {
+		return ""
+	}
+
+	release := "v" + parts[0]
+	if len(parts) > 1 {
+		release += "." + parts[1]
+	}
+	return release
+}
+
+// loadAlpineSecDB adds the advisories of a secdb file, or of every JSON file under a directory
+// such as a mirror of secdb.alpinelinux.org
+func (db *Database) loadAlpineSecDB(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return db.loadAlpineFile(path)
+	}
+
+	return filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".json") {
+			return nil
+		}
+		return db.loadAlpineFile(file)
+	})
+}
+
+func (db *Database) loadAlpineFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var secdb alpineSecDB
+	if err := json.Unmarshal(content, &secdb); err != nil {
+		return fmt.Errorf("invalid Alpine secdb %s: %v", path, err)
+	}
+	if secdb.DistroVersion == "" {
+		return fmt.Errorf("invalid Alpine secdb %s: missing distroversion", path)
+	}
+
+	for _, pkg := range secdb.Packages {
+		for fixed, fixes := range pkg.Pkg.SecFixes {
+			if fixed == "0" {
+				continue
+			}
+			for _, fix := range fixes {
+				// An entry may name several IDs, e.g. "CVE-2019-1543 CVE-2019-1547"
+				for _, id := range strings.Fields(fix) {
+					db.Add(types.EcosystemAlpine, secdb.DistroVersion, pkg.Pkg.Name, Advisory{ID: id, Fixed: fixed})
+				}
+			}
+		}
+	}
+
+	return nil
+}
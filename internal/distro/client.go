@@ -0,0 +1,133 @@
+package distro
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"khazande/internal/types"
+	versionsModule "khazande/internal/versions"
+	envsModule "khazande/pkg/envs"
+
+	"go.uber.org/zap"
+)
+
+// Client checks OS packages against the Alpine secdb of ALPINE_SECDB_PATH and the Debian security
+// tracker export of DEBIAN_SECURITY_TRACKER_PATH
+type Client struct {
+	Logger *zap.Logger
+	Envs   *envsModule.Envs
+
+	once     sync.Once
+	database *Database
+	loadErr  error
+}
+
+// Enabled reports whether the data of any distribution is configured
+func (client *Client) Enabled() bool {
+	return client != nil && (client.Envs.ALPINE_SECDB_PATH != "" || client.Envs.DEBIAN_SECURITY_TRACKER_PATH != "")
+}
+
+// Covers tells whether the data of the distribution of the package is configured
+func (client *Client) Covers(pkg types.Package) bool {
+	switch pkg.Ecosystem {
+	case types.EcosystemAlpine:
+		return client.Envs.ALPINE_SECDB_PATH != ""
+	case types.EcosystemDebian:
+		return client.Envs.DEBIAN_SECURITY_TRACKER_PATH != ""
+	}
+	return false
+}
+
+// FetchVulnerabilities returns the vulnerabilities of the OS packages keyed by types.PackageKey, like
+// Advisor.FetchVulnerabilitiesFromGithub. Packages of other ecosystems are ignored.
+func (client *Client) FetchVulnerabilities(packages []types.Package) map[string][]*types.Vulnerability {
+	vulnerabilities := make(map[string][]*types.Vulnerability)
+
+	database, err := client.Database()
+	if err != nil {
+		client.Logger.Sugar().Errorf("Failed to load the distribution security data: %v", err)
+		return vulnerabilities
+	}
+
+	for _, pkg := range packages {
+		if !client.Covers(pkg) {
+			continue
+		}
+		if pkg.Release == "" {
+			client.Logger.Sugar().Errorf("Skipping %s %s, the %s release it comes from is unknown", pkg.Name, pkg.Version, pkg.Ecosystem)
+			continue
+		}
+
+		for _, advisory := range database.Advisories(pkg.Ecosystem, pkg.Release, pkg.Name) {
+			vulnerability := toVulnerability(pkg, advisory)
+			if advisory.Fixed != "" {
+				result, err := versionsModule.Compare(pkg.Ecosystem, pkg.Version, advisory.Fixed)
+				switch {
+				case err != nil:
+					client.Logger.Sugar().Errorf("Error comparing %s %s with the fix of %s: %v", pkg.Name, pkg.Version, advisory.ID, err)
+					vulnerability.Match = types.MatchUnknown
+					vulnerability.MatchReason = fmt.Sprintf("comparing %s with the fixed version %s failed: %v", pkg.Version, advisory.Fixed, err)
+				case result >= 0:
+					continue
+				}
+			}
+
+			key := types.PackageKey(pkg.Ecosystem, pkg.Name)
+			vulnerabilities[key] = append(vulnerabilities[key], vulnerability)
+		}
+	}
+
+	return vulnerabilities
+}
+
+// Database loads the data of the distributions on first use
+func (client *Client) Database() (*Database, error) {
+	client.once.Do(func() {
+		client.database, client.loadErr = Load(client.Envs.ALPINE_SECDB_PATH, client.Envs.DEBIAN_SECURITY_TRACKER_PATH)
+		if client.loadErr == nil {
+			client.Logger.Info(fmt.Sprintf("Loaded %d Alpine and Debian advisories", client.database.Len()))
+		}
+	})
+
+	return client.database, client.loadErr
+}
+
+func toVulnerability(pkg types.Package, advisory Advisory) *types.Vulnerability {
+	vulnerability := &types.Vulnerability{
+		Name:             pkg.Name,
+		Ecosystem:        pkg.Ecosystem,
+		Version:          pkg.Version,
+		Indirect:         pkg.Indirect,
+		Line:             pkg.Line,
+		BomRef:           pkg.BomRef,
+		Layer:            pkg.Layer,
+		Location:         pkg.Location,
+		Summary:          advisory.Summary,
+		Severity:         advisory.Severity,
+		AffectedVersions: ">= 0",
+		PatchedVersions:  advisory.Fixed,
+		Match:            types.MatchAffected,
+	}
+
+	if advisory.Fixed != "" {
+		vulnerability.AffectedVersions = "< " + advisory.Fixed
+	}
+
+	if strings.HasPrefix(advisory.ID, "CVE-") {
+		vulnerability.CVEID = advisory.ID
+	} else {
+		vulnerability.Aliases = []string{advisory.ID}
+	}
+
+	switch pkg.Ecosystem {
+	case types.EcosystemAlpine:
+		vulnerability.Sources = []string{types.SourceAlpine}
+		vulnerability.References = []string{"https://security.alpinelinux.org/vuln/" + advisory.ID}
+	case types.EcosystemDebian:
+		vulnerability.Sources = []string{types.SourceDebian}
+		vulnerability.References = []string{"https://security-tracker.debian.org/tracker/" + advisory.ID}
+	}
+
+	return vulnerability
+}
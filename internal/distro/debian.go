@@ -0,0 +1,87 @@
+package distro
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"khazande/internal/types"
+)
+
+// debianReleases maps the codenames of the security tracker to the VERSION_ID of os-release
+var debianReleases = map[string]string{
+	"jessie":   "8",
+	"stretch":  "9",
+	"buster":   "10",
+	"bullseye": "11",
+	"bookworm": "12",
+	"trixie":   "13",
+	"forky":    "14",
+}
+
+// debianTracker is the JSON export of https://security-tracker.debian.org/tracker/data/json,
+// source package to vulnerability ID to details
+type debianTracker map[string]map[string]struct {
+	Description string `json:"description"`
+	Releases    map[string]struct {
+		Status       string `json:"status"`
+		FixedVersion string `json:"fixed_version"`
+		Urgency      string `json:"urgency"`
+	} `json:"releases"`
+}
+
+// loadDebianTracker adds the advisories of the JSON export of the Debian security tracker
+func (db *Database) loadDebianTracker(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var tracker debianTracker
+	if err := json.Unmarshal(content, &tracker); err != nil {
+		return fmt.Errorf("invalid Debian security tracker data %s: %v", path, err)
+	}
+
+	for name, vulnerabilities := range tracker {
+		for id, vulnerability := range vulnerabilities {
+			for codename, release := range vulnerability.Releases {
+				advisory := Advisory{ID: id, Summary: vulnerability.Description, Severity: debianSeverity(release.Urgency)}
+				switch release.Status {
+				case "resolved":
+					// A fixed version of 0 means the release never shipped the vulnerable code
+					if release.FixedVersion == "" || release.FixedVersion == "0" {
+						continue
+					}
+					advisory.Fixed = release.FixedVersion
+				case "open":
+				default:
+					// "undetermined" entries aren't known to affect the release yet
+					continue
+				}
+
+				version, ok := debianReleases[codename]
+				if !ok {
+					version = codename
+				}
+				db.Add(types.EcosystemDebian, version, name, advisory)
+			}
+		}
+	}
+
+	return nil
+}
+
+// debianSeverity maps the urgency the security team gave a vulnerability, such as "low**" for
+// a guess, to the GitHub severities
+func debianSeverity(urgency string) string {
+	switch strings.TrimRight(urgency, "*") {
+	case "unimportant", "low":
+		return "LOW"
+	case "medium":
+		return "MODERATE"
+	case "high":
+		return "HIGH"
+	}
+	return ""
+}
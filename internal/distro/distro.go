@@ -0,0 +1,62 @@
+package distro
+
+import (
+	"strings"
+
+	"khazande/internal/types"
+)
+
+// Advisory is the fix of a vulnerability in a source package of a distribution release
+type Advisory struct {
+	ID string
+	// Fixed is the first fixed version, empty while the release has no fix
+	Fixed    string
+	Summary  string
+	Severity string
+}
+
+// Database holds the advisories of the distributions indexed by release and source package
+type Database struct {
+	advisories map[string][]Advisory
+	size       int
+}
+
+// Load reads the Alpine secdb files of alpinePath and the Debian security tracker export of
+// debianPath, either of them may be empty
+func Load(alpinePath string, debianPath string) (*Database, error) {
+	db := &Database{advisories: make(map[string][]Advisory)}
+
+	if alpinePath != "" {
+		if err := db.loadAlpineSecDB(alpinePath); err != nil {
+			return nil, err
+		}
+	}
+	if debianPath != "" {
+		if err := db.loadDebianTracker(debianPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return db, nil
+}
+
+// Add records an advisory of a package of a release, e.g. openssl of Alpine v3.18
+func (db *Database) Add(ecosystem types.Ecosystem, release string, name string, advisory Advisory) {
+	key := advisoryKey(ecosystem, release, name)
+	db.advisories[key] = append(db.advisories[key], advisory)
+	db.size++
+}
+
+// Advisories returns the advisories of a package of a release
+func (db *Database) Advisories(ecosystem types.Ecosystem, release string, name string) []Advisory {
+	return db.advisories[advisoryKey(ecosystem, release, name)]
+}
+
+// Len is the number of advisories
+func (db *Database) Len() int {
+	return db.size
+}
+
+func advisoryKey(ecosystem types.Ecosystem, release string, name string) string {
+	return string(ecosystem) + "|" + release + "|" + strings.ToLower(name)
+}
@@ -0,0 +1,70 @@
+package gobinary
+
+import (
+	"bytes"
+	"debug/buildinfo"
+	"fmt"
+
+	"khazande/internal/types"
+	versionsModule "khazande/internal/versions"
+)
+
+// Binary is what a Go executable tells about the way it was built
+type Binary struct {
+	GoVersion  string
+	Path       string
+	MainModule types.Package
+	Packages   []types.Package
+}
+
+// Read extracts the modules embedded by the go command in an executable (ELF, Mach-O, PE, ...).
+// The packages are the dependencies, replaced by their replacement, the main module when it has a
+// version and the standard library at the version of the toolchain.
+func Read(content []byte) (*Binary, error) {
+	info, err := buildinfo.Read(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the build info of the binary: %v", err)
+	}
+
+	binary := &Binary{
+		GoVersion: info.GoVersion,
+		Path:      info.Path,
+		MainModule: types.Package{
+			Name:      info.Main.Path,
+			Version:   info.Main.Version,
+			Ecosystem: types.EcosystemGo,
+		},
+	}
+
+	// Modules built from their source directory are versioned (devel)
+	if binary.MainModule.Name != "" && binary.MainModule.Version != "" && binary.MainModule.Version != "(devel)" {
+		binary.Packages = append(binary.Packages, binary.MainModule)
+	}
+
+	for _, dep := range info.Deps {
+		module := dep
+		if dep.Replace != nil {
+			module = dep.Replace
+		}
+		// Replacements by a local directory have no version to check
+		if module.Version == "" || module.Version == "(devel)" {
+			continue
+		}
+
+		binary.Packages = append(binary.Packages, types.Package{
+			Name:      module.Path,
+			Version:   module.Version,
+			Ecosystem: types.EcosystemGo,
+		})
+	}
+
+	if version, ok := versionsModule.GoSemver(info.GoVersion); ok {
+		binary.Packages = append(binary.Packages, types.Package{
+			Name:      types.GoStdlib,
+			Version:   version,
+			Ecosystem: types.EcosystemGo,
+		})
+	}
+
+	return binary, nil
+}
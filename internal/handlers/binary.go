@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"fmt"
+
+	aggregatorModule "khazande/internal/aggregator"
+	gobinaryModule "khazande/internal/gobinary"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BinaryHandler checks the modules a Go executable was built with, read from its embedded build
+// info, and the standard library of the toolchain that built it. The executable is either the body
+// of the request or a multipart/form-data file under the "binary" field.
+func (h *Handler) BinaryHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		format, err := responseFormat(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		}
+
+		sources, err := aggregatorModule.ParseSources(c.Query("sources"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		}
+
+		content, uri, err := readUpload(c, "binary")
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		}
+
+		binary, err := gobinaryModule.Read(content)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		}
+
+		h.Aggregator.Logger.Info(fmt.Sprintf("Checking %d modules of %s built with %s", len(binary.Packages), binary.Path, binary.GoVersion))
+		vulerabilities := h.Aggregator.FetchVulnerabilities(binary.Packages, sources)
+
+		return render(c, format, vulerabilities, uri)
+	}
+}
@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	purlModule "khazande/internal/purl"
+	"khazande/internal/types"
+
+	"github.com/google/uuid"
+)
+
+const cycloneDXSpecVersion = "1.5"
+
+type cycloneDXDocument struct {
+	BomFormat       string                   `json:"bomFormat"`
+	SpecVersion     string                   `json:"specVersion"`
+	SerialNumber    string                   `json:"serialNumber"`
+	Version         int                      `json:"version"`
+	Metadata        cycloneDXMetadata        `json:"metadata"`
+	Components      []cycloneDXComponent     `json:"components"`
+	Vulnerabilities []cycloneDXVulnerability `json:"vulnerabilities"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string `json:"timestamp"`
+	Tools     struct {
+		Components []cycloneDXComponent `json:"components"`
+	} `json:"tools"`
+}
+
+type cycloneDXComponent struct {
+	Type       string              `json:"type"`
+	BomRef     string              `json:"bom-ref,omitempty"`
+	Name       string              `json:"name"`
+	Version    string              `json:"version,omitempty"`
+	Purl       string              `json:"purl,omitempty"`
+	Properties []cycloneDXProperty `json:"properties,omitempty"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cycloneDXVulnerability struct {
+	BomRef         string               `json:"bom-ref"`
+	ID             string               `json:"id"`
+	Source         *cycloneDXSource     `json:"source,omitempty"`
+	References     []cycloneDXReference `json:"references,omitempty"`
+	Ratings        []cycloneDXRating    `json:"ratings"`
+	Description    string               `json:"description,omitempty"`
+	Detail         string               `json:"detail,omitempty"`
+	Recommendation string               `json:"recommendation,omitempty"`
+	Advisories     []cycloneDXAdvisory  `json:"advisories,omitempty"`
+	Analysis       *cycloneDXAnalysis   `json:"analysis,omitempty"`
+	Published      string               `json:"published,omitempty"`
+	Updated        string               `json:"updated,omitempty"`
+	Affects        []cycloneDXAffect    `json:"affects"`
+}
+
+type cycloneDXSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type cycloneDXReference struct {
+	ID     string          `json:"id"`
+	Source cycloneDXSource `json:"source"`
+}
+
+type cycloneDXRating struct {
+	Source   *cycloneDXSource `json:"source,omitempty"`
+	Score    float64          `json:"score,omitempty"`
+	Severity string           `json:"severity"`
+	Method   string           `json:"method,omitempty"`
+	Vector   string           `json:"vector,omitempty"`
+}
+
+type cycloneDXAdvisory struct {
+	URL string `json:"url"`
+}
+
+type cycloneDXAnalysis struct {
+	State         string `json:"state"`
+	Justification string `json:"justification,omitempty"`
+	Detail        string `json:"detail,omitempty"`
+}
+
+type cycloneDXAffect struct {
+	Ref      string                   `json:"ref"`
+	Versions []cycloneDXAffectVersion `json:"versions"`
+}
+
+type cycloneDXAffectVersion struct {
+	Version string `json:"version"`
+	Status  string `json:"status"`
+}
+
+// renderCycloneDXResult builds a CycloneDX document listing the vulnerable components and a
+// vulnerabilities section with the ratings, the affected component refs and the patched version
+func renderCycloneDXResult(vulerabilities map[string][]*types.Vulnerability) cycloneDXDocument {
+	document := cycloneDXDocument{
+		BomFormat:       "CycloneDX",
+		SpecVersion:     cycloneDXSpecVersion,
+		SerialNumber:    "urn:uuid:" + uuid.NewString(),
+		Version:         1,
+		Components:      []cycloneDXComponent{},
+		Vulnerabilities: []cycloneDXVulnerability{},
+	}
+	document.Metadata.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	document.Metadata.Tools.Components = []cycloneDXComponent{{Type: "application", Name: "khazande"}}
+
+	components := make(map[string]bool)
+	vulnerabilityIndexes := make(map[string]int)
+
+	packages := make([]string, 0, len(vulerabilities))
+	for pkg := range vulerabilities {
+		packages = append(packages, pkg)
+	}
+	sort.Strings(packages)
+
+	for _, pkg := range packages {
+		for _, vulnerability := range vulerabilities[pkg] {
+			component := cycloneDXComponentOf(vulnerability)
+			if !components[component.BomRef] {
+				components[component.BomRef] = true
+				document.Components = append(document.Components, component)
+			}
+
+			affect := cycloneDXAffect{
+				Ref:      component.BomRef,
+				Versions: []cycloneDXAffectVersion{{Version: vulnerability.Version, Status: cycloneDXStatus(vulnerability)}},
+			}
+
+			// An advisory shared by several components is listed once with all of them as affected
+			id := vulnerabilityID(vulnerability)
+			if index, ok := vulnerabilityIndexes[id]; ok {
+				document.Vulnerabilities[index].Affects = append(document.Vulnerabilities[index].Affects, affect)
+				continue
+			}
+
+			vulnerabilityIndexes[id] = len(document.Vulnerabilities)
+			entry := cycloneDXVulnerabilityOf(id, vulnerability)
+			entry.Affects = []cycloneDXAffect{affect}
+			document.Vulnerabilities = append(document.Vulnerabilities, entry)
+		}
+	}
+
+	return document
+}
+
+// cycloneDXComponentOf keeps the bom-ref of SBOM components, other packages are referenced by purl
+func cycloneDXComponentOf(vulnerability *types.Vulnerability) cycloneDXComponent {
+	purl := purlModule.FromPackage(types.Package{
+		Name:      vulnerability.Name,
+		Version:   vulnerability.Version,
+		Ecosystem: vulnerability.Ecosystem,
+	}).String()
+
+	bomRef := vulnerability.BomRef
+	if bomRef == "" {
+		bomRef = purl
+	}
+
+	component := cycloneDXComponent{
+		Type:    "library",
+		BomRef:  bomRef,
+		Name:    vulnerability.Name,
+		Version: vulnerability.Version,
+		Purl:    purl,
+	}
+	// Where in a container image the package was found
+	if vulnerability.Layer != "" {
+		component.Properties = append(component.Properties, cycloneDXProperty{Name: "khazande:image:layer", Value: vulnerability.Layer})
+	}
+	if vulnerability.Location != "" {
+		component.Properties = append(component.Properties, cycloneDXProperty{Name: "khazande:image:location", Value: vulnerability.Location})
+	}
+
+	return component
+}
+
+func cycloneDXVulnerabilityOf(id string, vulnerability *types.Vulnerability) cycloneDXVulnerability {
+	entry := cycloneDXVulnerability{
+		BomRef:      id,
+		ID:          id,
+		Description: vulnerability.Summary,
+		Detail:      vulnerability.Description,
+		Published:   cycloneDXTime(vulnerability.PublishedDate),
+		Updated:     cycloneDXTime(vulnerability.LastModified),
+	}
+
+	switch {
+	case strings.HasPrefix(id, "GHSA-"):
+		entry.Source = &cycloneDXSource{Name: "GitHub", URL: "https://github.com/advisories/" + id}
+	case strings.HasPrefix(id, "CVE-"):
+		entry.Source = &cycloneDXSource{Name: "NVD", URL: "https://nvd.nist.gov/vuln/detail/" + id}
+	}
+
+	if vulnerability.CVEID != "" && vulnerability.CVEID != id {
+		entry.References = append(entry.References, cycloneDXReference{
+			ID:     vulnerability.CVEID,
+			Source: cycloneDXSource{Name: "NVD", URL: "https://nvd.nist.gov/vuln/detail/" + vulnerability.CVEID},
+		})
+	}
+
+	rating := cycloneDXRating{Source: entry.Source, Severity: cycloneDXSeverity(vulnerability.Severity)}
+	if fields := strings.Fields(vulnerability.NVDScore); len(fields) != 0 {
+		if score, err := strconv.ParseFloat(fields[0], 64); err == nil {
+			rating.Score = score
+		}
+	}
+	if vulnerability.NVDVector != "" {
+		rating.Vector = vulnerability.NVDVector
+		rating.Method = cycloneDXMethod(vulnerability.NVDVector)
+	}
+	entry.Ratings = []cycloneDXRating{rating}
+
+	if vulnerability.PatchedVersions != "" {
+		entry.Recommendation = fmt.Sprintf("Upgrade %s to version %s or later", vulnerability.Name, vulnerability.PatchedVersions)
+	}
+
+	// The outcome of the reachability analysis of an uploaded source tree
+	switch vulnerability.Reachability {
+	case types.Reachable:
+		entry.Analysis = &cycloneDXAnalysis{State: "exploitable", Detail: "Called through " + strings.Join(vulnerability.CallStack, " -> ")}
+	case types.ModuleReachable:
+		// The advisory doesn't name the vulnerable functions, only the module is known to be used
+		entry.Analysis = &cycloneDXAnalysis{State: "in_triage", Detail: "The module is used through " + strings.Join(vulnerability.CallStack, " -> ")}
+	case types.Unreachable:
+		entry.Analysis = &cycloneDXAnalysis{State: "not_affected", Justification: "code_not_reachable"}
+	default:
+		if vulnerability.Match == types.MatchUnknown {
+			entry.Analysis = &cycloneDXAnalysis{State: "in_triage", Detail: vulnerability.MatchReason}
+		}
+	}
+
+	for _, reference := range vulnerability.References {
+		entry.Advisories = append(entry.Advisories, cycloneDXAdvisory{URL: reference})
+	}
+
+	return entry
+}
+
+// cycloneDXStatus is the status of the version of the component, unknown when it couldn't be checked
+func cycloneDXStatus(vulnerability *types.Vulnerability) string {
+	if vulnerability.Match == types.MatchUnknown {
+		return "unknown"
+	}
+	return "affected"
+}
+
+// cycloneDXMethod is the scoring method the version prefix of a CVSS vector names, none when the
+// vector has no prefix CycloneDX knows
+func cycloneDXMethod(vector string) string {
+	switch {
+	case strings.HasPrefix(vector, "CVSS:3.0/"):
+		return "CVSSv3"
+	case strings.HasPrefix(vector, "CVSS:3.1/"):
+		return "CVSSv31"
+	case strings.HasPrefix(vector, "CVSS:4.0/"):
+		return "CVSSv4"
+	default:
+		return ""
+	}
+}
+
+func cycloneDXSeverity(severity string) string {
+	switch strings.ToUpper(severity) {
+	case "CRITICAL":
+		return "critical"
+	case "HIGH":
+		return "high"
+	case "MODERATE", "MEDIUM":
+		return "medium"
+	case "LOW":
+		return "low"
+	default:
+		return "unknown"
+	}
+}
+
+// cycloneDXTime converts the dates kept in types.Vulnerability to RFC 3339, dropping the ones it can't parse
+func cycloneDXTime(date string) string {
+	parsed, err := types.ParseDate(date)
+	if err != nil {
+		return ""
+	}
+
+	return parsed.UTC().Format(time.RFC3339)
+}
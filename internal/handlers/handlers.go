@@ -3,58 +3,338 @@ package handlers
 import (
 	"bytes"
 	"fmt"
+	"io"
 	advisorModule "khazande/internal/advisor"
+	aggregatorModule "khazande/internal/aggregator"
+	distroModule "khazande/internal/distro"
+	osvModule "khazande/internal/osv"
+	parserModule "khazande/internal/parser"
+	reachabilityModule "khazande/internal/reachability"
 	"khazande/internal/types"
+	vulndbModule "khazande/internal/vulndb"
 	envsModule "khazande/pkg/envs"
-	"regexp"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/jedib0t/go-pretty/table"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
 type Handler struct {
-	Advisor *advisorModule.Advisor
+	Aggregator *aggregatorModule.Aggregator
 }
 
-func Initial(envs *envsModule.Envs, logger *zap.Logger) *Handler {
+func Initial(envs *envsModule.Envs, logger *zap.Logger, redisClient *redis.Client) *Handler {
 	return &Handler{
-		Advisor: &advisorModule.Advisor{
-			Logger: logger,
-			Envs:   envs,
+		Aggregator: &aggregatorModule.Aggregator{
+			Logger:      logger,
+			Envs:        envs,
+			RedisClient: redisClient,
+			Advisor: &advisorModule.Advisor{
+				Logger: logger,
+				Envs:   envs,
+			},
+			OSV: &osvModule.Client{
+				Logger: logger,
+				Envs:   envs,
+			},
+			VulnDB: &vulndbModule.Client{
+				Logger: logger,
+				Envs:   envs,
+			},
+			Distro: &distroModule.Client{
+				Logger: logger,
+				Envs:   envs,
+			},
 		},
 	}
 }
 
 func (h *Handler) VulnerabilityHandler() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// Regular expression to match package names and versions
-		re := regexp.MustCompile(`\s*([^ \n\r\t]+)\s+v([0-9]+\.[0-9]+\.[0-9]+)`)
+		format, err := responseFormat(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		}
+
+		graph, err := sourceGraph(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		}
 
-		matches := re.FindAllStringSubmatch(string(c.Body()), -1)
+		packages, err := parsePackages(c, graph)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		}
 
-		packages := make(map[string]string)
+		// ?sources=github,osv,nvd merges the findings of GitHub and OSV and adds the NVD details to them
+		sources, err := aggregatorModule.ParseSources(c.Query("sources"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		}
 
-		for _, match := range matches {
-			if len(match) == 3 {
-				packages[match[1]] = match[2]
-			}
+		vulerabilities := h.Aggregator.FetchVulnerabilities(packages, sources)
+
+		if graph != nil {
+			checkReachability(graph, vulerabilities, c.QueryBool("hide-unreachable"))
 		}
 
-		vulerabilities := h.Advisor.FetchVulnerabilitiesFromGithub(packages)
+		return render(c, format, vulerabilities, manifestURI(c))
+	}
+}
+
+// render writes the findings in the requested format, SARIF results point at the manifest URI.
+// With ?fail-on-unknown=true the report comes with a 422 status when some finding is undetermined.
+func render(c *fiber.Ctx, format string, vulerabilities map[string][]*types.Vulnerability, uri string) error {
+	status := fiber.StatusOK
+	if c.QueryBool("fail-on-unknown") && hasUndetermined(vulerabilities) {
+		status = fiber.StatusUnprocessableEntity
+	}
 
+	switch format {
+	case formatJSON:
+		return c.Status(status).JSON(renderJSONResult(vulerabilities))
+	case formatSARIF:
+		return c.Status(status).JSON(renderSARIFResult(vulerabilities, uri), mimeSARIF)
+	case formatCycloneDX:
+		return c.Status(status).JSON(renderCycloneDXResult(vulerabilities), mimeCycloneDX)
+	default:
 		result := renderTableResult(vulerabilities)
 
-		return c.Status(200).SendString(result)
+		return c.Status(status).SendString(result)
+	}
+}
+
+// hasUndetermined tells whether the version of some package couldn't be checked against an advisory
+func hasUndetermined(vulerabilities map[string][]*types.Vulnerability) bool {
+	for _, packageVulnerabilities := range vulerabilities {
+		for _, vulnerability := range packageVulnerabilities {
+			if vulnerability.Match == types.MatchUnknown {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+const (
+	formatTable     = "table"
+	formatJSON      = "json"
+	formatSARIF     = "sarif"
+	formatCycloneDX = "cyclonedx"
+
+	mimeSARIF     = "application/sarif+json"
+	mimeCycloneDX = "application/vnd.cyclonedx+json"
+)
+
+// responseFormat picks the output format from the ?format= query parameter, falling back to
+// the Accept header. The ASCII table stays the default.
+func responseFormat(c *fiber.Ctx) (string, error) {
+	switch format := strings.ToLower(c.Query("format")); format {
+	case formatTable, formatJSON, formatSARIF, formatCycloneDX:
+		return format, nil
+	case "":
+	default:
+		return "", fmt.Errorf("unsupported format %q", format)
+	}
+
+	switch c.Accepts(fiber.MIMETextPlain, fiber.MIMEApplicationJSON, mimeSARIF, mimeCycloneDX) {
+	case fiber.MIMEApplicationJSON:
+		return formatJSON, nil
+	case mimeSARIF:
+		return formatSARIF, nil
+	case mimeCycloneDX:
+		return formatCycloneDX, nil
+	default:
+		return formatTable, nil
+	}
+}
+
+// parsePackages extracts the packages to check from the request. The ?input= query parameter selects
+// the kind of document that is posted, otherwise the body is a manifest of the ?ecosystem= ecosystem.
+// The go.mod of an uploaded source tree is used when no go.mod is uploaded along with it.
+func parsePackages(c *fiber.Ctx, graph *reachabilityModule.Graph) ([]types.Package, error) {
+	switch c.Query("input") {
+	case "golist":
+		// The output of `go list -m -json all`
+		return parserModule.ParseGoList(c.Body())
+	case "cyclonedx":
+		return parserModule.ParseCycloneDX(c.Body())
+	case "spdx":
+		return parserModule.ParseSPDX(c.Body())
+	case "", "manifest":
+	default:
+		return nil, fmt.Errorf("unsupported input %q", c.Query("input"))
+	}
+
+	// The ecosystem decides how the body is parsed, Go modules are the default
+	ecosystem, err := parserModule.ParseEcosystem(c.Query("ecosystem", "go"))
+	if err != nil {
+		return nil, err
+	}
+
+	if ecosystem == types.EcosystemGo && isMultipart(c) {
+		// A go.mod and optionally its go.sum uploaded as multipart/form-data
+		goMod, err := readFormFile(c, "go.mod")
+		if err != nil && graph == nil {
+			return nil, err
+		}
+		goSum, _ := readFormFile(c, "go.sum")
+		if err != nil {
+			goMod, goSum = graph.GoMod, graph.GoSum
+		}
+		// An empty go.mod parses without error, the scan would look clean
+		if len(goMod) == 0 {
+			return nil, fmt.Errorf("no go.mod was uploaded and the source archive has none")
+		}
+
+		return parserModule.ParseGoMod(goMod, goSum)
+	}
+
+	return parserModule.Parse(ecosystem, c.Body())
+}
+
+// manifestNames are the files SARIF results point at when the request doesn't name one with ?path=
+var manifestNames = map[types.Ecosystem]string{
+	types.EcosystemGo:       "go.mod",
+	types.EcosystemNpm:      "package-lock.json",
+	types.EcosystemPip:      "requirements.txt",
+	types.EcosystemMaven:    "pom.xml",
+	types.EcosystemRubyGems: "Gemfile.lock",
+	types.EcosystemNuGet:    "packages.config",
+	types.EcosystemRust:     "Cargo.lock",
+	types.EcosystemComposer: "composer.lock",
+	types.EcosystemPub:      "pubspec.lock",
+}
+
+func manifestURI(c *fiber.Ctx) string {
+	if path := c.Query("path"); path != "" {
+		return path
+	}
+
+	switch c.Query("input") {
+	case "golist":
+		return "go.mod"
+	case "cyclonedx":
+		return "bom.json"
+	case "spdx":
+		return "sbom.spdx.json"
+	}
+
+	ecosystem, err := parserModule.ParseEcosystem(c.Query("ecosystem", "go"))
+	if err != nil {
+		return "go.mod"
+	}
+
+	return manifestNames[ecosystem]
+}
+
+// sourceGraph builds the call graph of a Go source tree uploaded as a zip, tar or tar.gz archive under
+// the "source" field of a multipart/form-data request. It is nil when no source tree is uploaded.
+func sourceGraph(c *fiber.Ctx) (*reachabilityModule.Graph, error) {
+	if !isMultipart(c) {
+		return nil, nil
+	}
+	if _, err := c.FormFile("source"); err != nil {
+		return nil, nil
+	}
+
+	archive, err := readFormFile(c, "source")
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := reachabilityModule.ReadArchive(archive)
+	if err != nil {
+		return nil, err
 	}
+
+	return reachabilityModule.Analyze(files)
+}
+
+// checkReachability marks how the Go findings are reached from the uploaded source tree, dropping
+// the ones proven unreachable when asked to with ?hide-unreachable=true
+func checkReachability(graph *reachabilityModule.Graph, vulerabilities map[string][]*types.Vulnerability, hideUnreachable bool) {
+	for pkg, packageVulnerabilities := range vulerabilities {
+		var kept []*types.Vulnerability
+		for _, vulnerability := range packageVulnerabilities {
+			graph.Check(vulnerability)
+			if hideUnreachable && vulnerability.Reachability == types.Unreachable {
+				continue
+			}
+			kept = append(kept, vulnerability)
+		}
+		vulerabilities[pkg] = kept
+	}
+}
+
+func isMultipart(c *fiber.Ctx) bool {
+	return strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm)
+}
+
+// readFormFile returns the content of a file uploaded as multipart/form-data under the given field
+// readUpload reads a file posted as the body of the request or as a multipart/form-data file under
+// field, along with the URI SARIF results point at: ?path=, else the name of the uploaded file
+func readUpload(c *fiber.Ctx, field string) ([]byte, string, error) {
+	uri := c.Query("path", field)
+	if !isMultipart(c) {
+		return c.Body(), uri, nil
+	}
+
+	fileHeader, err := c.FormFile(field)
+	if err != nil {
+		return nil, "", fmt.Errorf("missing %s file: %v", field, err)
+	}
+	if c.Query("path") == "" {
+		uri = fileHeader.Filename
+	}
+
+	content, err := readFormFile(c, field)
+	return content, uri, err
+}
+
+func readFormFile(c *fiber.Ctx, field string) ([]byte, error) {
+	fileHeader, err := c.FormFile(field)
+	if err != nil {
+		return nil, fmt.Errorf("missing %s file: %v", field, err)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s file: %v", field, err)
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
 }
 
 func renderTableResult(vulerabilities map[string][]*types.Vulnerability) string {
+	// The reachability column is only shown when a source tree was analyzed, the layer column
+	// when an image was and the match column when some version couldn't be checked
+	analyzed, layered, undetermined := false, false, false
+	for _, packageVulnerabilities := range vulerabilities {
+		for _, vulnerability := range packageVulnerabilities {
+			analyzed = analyzed || vulnerability.Reachability != ""
+			layered = layered || vulnerability.Layer != ""
+			undetermined = undetermined || vulnerability.Match == types.MatchUnknown
+		}
+	}
+
 	var buffer bytes.Buffer
 	t := table.NewWriter()
 	t.SetOutputMirror(&buffer)
-	t.AppendHeader(table.Row{"#", "Package", "Vulnerability", "Severity", "Affected Versions", "Fixed Version", "Title"})
+	header := table.Row{"#", "Package", "Dependency", "Vulnerability", "Severity", "Affected Versions", "Fixed Version", "Title"}
+	if analyzed {
+		header = append(header, "Reachability")
+	}
+	if layered {
+		header = append(header, "Layer", "Location")
+	}
+	if undetermined {
+		header = append(header, "Match")
+	}
+	t.AppendHeader(header)
 	style := table.Style{
 		Box: table.BoxStyle{
 			BottomLeft:       "+",
@@ -90,7 +370,7 @@ func renderTableResult(vulerabilities map[string][]*types.Vulnerability) string
 	// })
 	count := 1
 
-	for pkg, packageVulnerabilities := range vulerabilities {
+	for _, packageVulnerabilities := range vulerabilities {
 		for _, vulnerability := range packageVulnerabilities {
 			var title string
 			words := strings.Fields(vulnerability.Summary)
@@ -99,11 +379,34 @@ func renderTableResult(vulerabilities map[string][]*types.Vulnerability) string
 			} else {
 				title = fmt.Sprintf("%s %s %s %s %s %s ...", words[0], words[1], words[2], words[3], words[4], words[5])
 			}
-			t.AppendRow([]interface{}{count, pkg, vulnerability.CVEID, vulnerability.Severity, vulnerability.AffectedVersions, vulnerability.PatchedVersions, title})
+			dependency := "direct"
+			if vulnerability.Indirect {
+				dependency = "indirect"
+			}
+			row := []interface{}{count, vulnerability.Name, dependency, vulnerability.CVEID, vulnerability.Severity, vulnerability.AffectedVersions, vulnerability.PatchedVersions, title}
+			if analyzed {
+				reachability := vulnerability.Reachability
+				if len(vulnerability.CallStack) != 0 {
+					// The vulnerable symbol the call stack ends in
+					reachability = fmt.Sprintf("%s via %s", reachability, vulnerability.CallStack[len(vulnerability.CallStack)-1])
+				}
+				row = append(row, reachability)
+			}
+			if layered {
+				row = append(row, vulnerability.Layer, vulnerability.Location)
+			}
+			if undetermined {
+				match := vulnerability.Match
+				if vulnerability.Match == types.MatchUnknown {
+					match = fmt.Sprintf("%s: %s", match, vulnerability.MatchReason)
+				}
+				row = append(row, match)
+			}
+			t.AppendRow(row)
 			count += 1
 		}
 	}
-	t.AppendFooter(table.Row{"", "", "Total", count})
+	t.AppendFooter(table.Row{"", "", "", "Total", count})
 	t.Render()
 
 	return buffer.String()
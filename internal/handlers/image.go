@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"fmt"
+
+	aggregatorModule "khazande/internal/aggregator"
+	imageModule "khazande/internal/image"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ImageHandler checks the Go modules and OS packages found in a container image, given as the
+// tarball of `docker save` or of an OCI layout, optionally gzipped. The tarball is either the body
+// of the request or a multipart/form-data file under the "image" field.
+func (h *Handler) ImageHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		format, err := responseFormat(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		}
+
+		sources, err := aggregatorModule.ParseSources(c.Query("sources"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		}
+
+		content, uri, err := readUpload(c, "image")
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		}
+
+		image, err := imageModule.Read(content)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		}
+
+		if image.SkippedOSPackages != 0 {
+			h.Aggregator.Logger.Info(fmt.Sprintf("Skipping %d OS packages of %s %s, no source covers the distribution", image.SkippedOSPackages, image.OS, image.Release))
+		}
+		h.Aggregator.Logger.Info(fmt.Sprintf("Checking %d packages of %d layers of %s", len(image.Packages), len(image.Layers), uri))
+		vulerabilities := h.Aggregator.FetchVulnerabilities(image.Packages, sources)
+
+		return render(c, format, vulerabilities, uri)
+	}
+}
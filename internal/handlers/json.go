@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"sort"
+	"strings"
+
+	"khazande/internal/types"
+)
+
+type jsonResult struct {
+	Packages []jsonPackage `json:"packages"`
+	Summary  jsonSummary   `json:"summary"`
+}
+
+type jsonPackage struct {
+	Name            string                 `json:"name"`
+	Ecosystem       types.Ecosystem        `json:"ecosystem"`
+	Vulnerabilities []*types.Vulnerability `json:"vulnerabilities"`
+}
+
+type jsonSummary struct {
+	Packages        int            `json:"packages"`
+	Vulnerabilities int            `json:"vulnerabilities"`
+	Severities      map[string]int `json:"severities"`
+	// Findings whose version couldn't be checked against the advisory
+	Undetermined int `json:"undetermined"`
+}
+
+// severities reported by the GitHub Advisory Database, findings without one are counted as UNKNOWN
+var severities = []string{"CRITICAL", "HIGH", "MODERATE", "LOW", "UNKNOWN"}
+
+func renderJSONResult(vulerabilities map[string][]*types.Vulnerability) jsonResult {
+	result := jsonResult{
+		Packages: []jsonPackage{},
+		Summary:  jsonSummary{Severities: make(map[string]int)},
+	}
+	for _, severity := range severities {
+		result.Summary.Severities[severity] = 0
+	}
+
+	for _, packageVulnerabilities := range vulerabilities {
+		if len(packageVulnerabilities) == 0 {
+			continue
+		}
+
+		// The findings of a package all carry its name and ecosystem
+		first := packageVulnerabilities[0]
+		result.Packages = append(result.Packages, jsonPackage{Name: first.Name, Ecosystem: first.Ecosystem, Vulnerabilities: packageVulnerabilities})
+		result.Summary.Packages += 1
+
+		for _, vulnerability := range packageVulnerabilities {
+			result.Summary.Severities[jsonSeverity(vulnerability.Severity)] += 1
+			result.Summary.Vulnerabilities += 1
+			if vulnerability.Match == types.MatchUnknown {
+				result.Summary.Undetermined += 1
+			}
+		}
+	}
+
+	sort.Slice(result.Packages, func(i, j int) bool {
+		if result.Packages[i].Name != result.Packages[j].Name {
+			return result.Packages[i].Name < result.Packages[j].Name
+		}
+		return result.Packages[i].Ecosystem < result.Packages[j].Ecosystem
+	})
+
+	return result
+}
+
+// jsonSeverity maps the severities of the other sources onto the GitHub ones, NVD and OSV say MEDIUM
+// for MODERATE. Anything else is counted as UNKNOWN.
+func jsonSeverity(severity string) string {
+	switch strings.ToUpper(strings.TrimSpace(severity)) {
+	case "CRITICAL":
+		return "CRITICAL"
+	case "HIGH":
+		return "HIGH"
+	case "MODERATE", "MEDIUM":
+		return "MODERATE"
+	case "LOW":
+		return "LOW"
+	default:
+		return "UNKNOWN"
+	}
+}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"khazande/internal/types"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	FullDescription      sarifMessage       `json:"fullDescription"`
+	Help                 sarifHelp          `json:"help"`
+	HelpURI              string             `json:"helpUri,omitempty"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	Properties           sarifProperties    `json:"properties"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifHelp struct {
+	Text     string `json:"text"`
+	Markdown string `json:"markdown"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifProperties struct {
+	SecuritySeverity string   `json:"security-severity"`
+	Tags             []string `json:"tags"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// renderSARIFResult turns every advisory into a rule and every vulnerable dependency into a result
+// located at the line of the manifest that declares it, when that line is known
+func renderSARIFResult(vulerabilities map[string][]*types.Vulnerability, manifestURI string) sarifLog {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "khazande",
+			InformationURI: "https://github.com/mahdimahdavi-ce/khazande",
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+	ruleIndexes := make(map[string]int)
+
+	packages := make([]string, 0, len(vulerabilities))
+	for pkg := range vulerabilities {
+		packages = append(packages, pkg)
+	}
+	sort.Strings(packages)
+
+	for _, pkg := range packages {
+		for _, vulnerability := range vulerabilities[pkg] {
+			ruleID := vulnerabilityID(vulnerability)
+
+			index, ok := ruleIndexes[ruleID]
+			if !ok {
+				index = len(run.Tool.Driver.Rules)
+				ruleIndexes[ruleID] = index
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRuleOf(ruleID, vulnerability))
+			}
+
+			message := fmt.Sprintf("%s %s is affected by %s: %s", vulnerability.Name, vulnerability.Version, ruleID, vulnerability.Summary)
+			if vulnerability.Match == types.MatchUnknown {
+				message = fmt.Sprintf("%s %s may be affected by %s, %s: %s", vulnerability.Name, vulnerability.Version, ruleID, vulnerability.MatchReason, vulnerability.Summary)
+			}
+			if vulnerability.PatchedVersions != "" {
+				message += fmt.Sprintf(". Upgrade to %s or later", vulnerability.PatchedVersions)
+			}
+			if vulnerability.Layer != "" {
+				message += fmt.Sprintf(". Introduced by layer %s", vulnerability.Layer)
+			}
+
+			uri := manifestURI
+			if vulnerability.Location != "" {
+				// The file of the image the package was found in
+				uri = strings.TrimPrefix(vulnerability.Location, "/")
+			}
+			location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: uri},
+			}}
+			if vulnerability.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: vulnerability.Line}
+			}
+
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    ruleID,
+				RuleIndex: index,
+				Level:     sarifLevel(vulnerability.Severity),
+				Message:   sarifMessage{Text: message},
+				Locations: []sarifLocation{location},
+			})
+		}
+	}
+
+	return sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}}
+}
+
+func sarifRuleOf(ruleID string, vulnerability *types.Vulnerability) sarifRule {
+	var markdown strings.Builder
+	fmt.Fprintf(&markdown, "**%s**\n\n%s\n", vulnerability.Summary, vulnerability.Description)
+	if len(vulnerability.References) != 0 {
+		markdown.WriteString("\n#### References\n")
+		for _, reference := range vulnerability.References {
+			fmt.Fprintf(&markdown, "- %s\n", reference)
+		}
+	}
+
+	rule := sarifRule{
+		ID:                   ruleID,
+		Name:                 vulnerability.Summary,
+		ShortDescription:     sarifMessage{Text: vulnerability.Summary},
+		FullDescription:      sarifMessage{Text: vulnerability.Description},
+		Help:                 sarifHelp{Text: vulnerability.Description, Markdown: markdown.String()},
+		DefaultConfiguration: sarifConfiguration{Level: sarifLevel(vulnerability.Severity)},
+		Properties: sarifProperties{
+			SecuritySeverity: securitySeverity(vulnerability),
+			Tags:             []string{"security", "vulnerability", strings.ToLower(string(vulnerability.Ecosystem))},
+		},
+	}
+	if len(vulnerability.References) != 0 {
+		rule.HelpURI = vulnerability.References[0]
+	}
+
+	return rule
+}
+
+// vulnerabilityID prefers the GitHub advisory ID, which every GitHub finding has, over the CVE ID.
+// Advisories with neither, such as GO-, RUSTSEC- or Alpine entries, go by their own ID, which is the
+// first alias.
+func vulnerabilityID(vulnerability *types.Vulnerability) string {
+	switch {
+	case vulnerability.GHSAID != "":
+		return vulnerability.GHSAID
+	case vulnerability.CVEID != "":
+		return vulnerability.CVEID
+	case len(vulnerability.Aliases) != 0:
+		return vulnerability.Aliases[0]
+	default:
+		return vulnerability.Name
+	}
+}
+
+func sarifLevel(severity string) string {
+	switch strings.ToUpper(severity) {
+	case "CRITICAL", "HIGH":
+		return "error"
+	case "MODERATE", "MEDIUM":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// securitySeverity is the numeric score code-scanning dashboards sort and filter by. The CVSS score is
+// used when there is one, otherwise the severity is mapped to the middle of its CVSS band.
+func securitySeverity(vulnerability *types.Vulnerability) string {
+	// NVD scores look like "7.5 HIGH"
+	if fields := strings.Fields(vulnerability.NVDScore); len(fields) != 0 {
+		if score, err := strconv.ParseFloat(fields[0], 64); err == nil && score > 0 {
+			return strconv.FormatFloat(score, 'f', 1, 64)
+		}
+	}
+
+	switch strings.ToUpper(vulnerability.Severity) {
+	case "CRITICAL":
+		return "9.5"
+	case "HIGH":
+		return "8.0"
+	case "MODERATE", "MEDIUM":
+		return "5.5"
+	case "LOW":
+		return "2.0"
+	default:
+		return "0.0"
+	}
+}
@@ -0,0 +1,286 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	gobinaryModule "khazande/internal/gobinary"
+	parserModule "khazande/internal/parser"
+	"khazande/internal/types"
+)
+
+const (
+	whiteoutPrefix = ".wh."
+	// An opaque whiteout hides the whole content of its directory in the lower layers
+	opaqueWhiteout = ".wh..wh..opq"
+)
+
+type fileKind int
+
+const (
+	goBinary fileKind = iota
+	goMod
+	apkDatabase
+	dpkgDatabase
+	osReleaseFile
+)
+
+// file is a file of the image the packages are read from, as left by the last layer writing it
+type file struct {
+	kind     fileKind
+	content  []byte
+	packages []types.Package
+}
+
+// filesystem follows the files of interest through the layers, like an overlay mount would
+type filesystem struct {
+	files map[string]*file
+	// removed are the files the current layer deleted, a layer replacing a directory keeps the
+	// layer that introduced the packages it still has
+	removed map[string]*file
+	budget  *sizeBudget
+}
+
+type entry struct {
+	header  *tar.Header
+	name    string
+	content []byte
+}
+
+func newFilesystem(budget *sizeBudget) *filesystem {
+	return &filesystem{files: make(map[string]*file), budget: budget}
+}
+
+// apply unpacks a layer on top of the previous ones. Whiteouts only hide the files of the layers
+// below, so they are all applied before the files the layer adds.
+func (fs *filesystem) apply(l layer) error {
+	reader, err := decompress(l.content)
+	if err != nil {
+		return err
+	}
+
+	var whiteouts, additions []entry
+	archive := tar.NewReader(reader)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar archive: %v", err)
+		}
+
+		name := cleanName(header.Name)
+		if strings.HasPrefix(path.Base(name), whiteoutPrefix) {
+			whiteouts = append(whiteouts, entry{header: header, name: name})
+			continue
+		}
+
+		e := entry{header: header, name: name}
+		if header.Typeflag == tar.TypeReg && candidate(name, header.Mode) {
+			e.content, err = fs.budget.read(name, archive)
+			if err != nil {
+				return err
+			}
+		}
+		additions = append(additions, e)
+	}
+
+	fs.removed = make(map[string]*file)
+	for _, whiteout := range whiteouts {
+		dir, base := path.Split(whiteout.name)
+		if base == opaqueWhiteout {
+			fs.remove(strings.TrimSuffix(dir, "/"), false)
+		} else {
+			fs.remove(path.Join(dir, strings.TrimPrefix(base, whiteoutPrefix)), true)
+		}
+	}
+
+	for _, addition := range additions {
+		if addition.header.Typeflag == tar.TypeDir {
+			continue
+		}
+		previous := fs.files[addition.name]
+		if previous == nil {
+			previous = fs.removed[addition.name]
+		}
+		delete(fs.files, addition.name)
+
+		if addition.content == nil {
+			continue
+		}
+		if f := readFile(addition.name, addition.content); f != nil {
+			f.introducedIn(l.id, previous)
+			fs.files[addition.name] = f
+		}
+	}
+
+	return nil
+}
+
+// remove deletes the content of a directory and, unless only its content goes, the path itself
+func (fs *filesystem) remove(name string, self bool) {
+	prefix := name + "/"
+	if name == "." || name == "" {
+		prefix = ""
+	}
+
+	for current, f := range fs.files {
+		if (self && current == name) || strings.HasPrefix(current, prefix) {
+			fs.removed[current] = f
+			delete(fs.files, current)
+		}
+	}
+}
+
+// introducedIn sets the layer of the packages of a file written by the layer, the packages the
+// file already had before being rewritten keep the layer that introduced them
+func (f *file) introducedIn(layer string, previous *file) {
+	layers := make(map[string]string)
+	if previous != nil {
+		for _, pkg := range previous.packages {
+			layers[pkg.Name+"@"+pkg.Version] = pkg.Layer
+		}
+	}
+
+	for i, pkg := range f.packages {
+		f.packages[i].Layer = layer
+		if introduced, ok := layers[pkg.Name+"@"+pkg.Version]; ok {
+			f.packages[i].Layer = introduced
+		}
+	}
+}
+
+// candidate tells whether the content of a file is needed to know whether it's of interest,
+// executables are only known to be Go binaries once read
+func candidate(name string, mode int64) bool {
+	_, ok := kindOf(name)
+	return ok || mode&0111 != 0
+}
+
+func kindOf(name string) (fileKind, bool) {
+	dir, base := path.Split(name)
+	switch {
+	case name == "lib/apk/db/installed":
+		return apkDatabase, true
+	case name == "var/lib/dpkg/status":
+		return dpkgDatabase, true
+	case dir == "var/lib/dpkg/status.d/" && !strings.HasSuffix(base, ".md5sums"):
+		// Distroless images have one status file per package instead
+		return dpkgDatabase, true
+	case name == "etc/os-release" || name == "usr/lib/os-release":
+		return osReleaseFile, true
+	case base == "go.mod" && !skipGoMod(name):
+		return goMod, true
+	}
+	return 0, false
+}
+
+// skipGoMod leaves out the go.mod files of the module cache, vendored modules, test data and the
+// Go distribution itself, which aren't what the image runs
+func skipGoMod(name string) bool {
+	if strings.HasPrefix(name, "usr/local/go/") || strings.HasPrefix(name, "usr/lib/go") {
+		return true
+	}
+	for _, dir := range []string{"pkg/mod", "vendor", "testdata"} {
+		if strings.HasPrefix(name, dir+"/") || strings.Contains(name, "/"+dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// readFile reads the packages of a file, files of no interest give nil
+func readFile(name string, content []byte) *file {
+	location := "/" + name
+
+	kind, ok := kindOf(name)
+	if !ok {
+		if !bytes.HasPrefix(content, []byte("\x7fELF")) {
+			return nil
+		}
+		binary, err := gobinaryModule.Read(content)
+		if err != nil {
+			// Not built by the go command
+			return nil
+		}
+		return &file{kind: goBinary, packages: located(binary.Packages, location)}
+	}
+
+	f := &file{kind: kind}
+	switch kind {
+	case osReleaseFile:
+		f.content = content
+	case goMod:
+		packages, err := parserModule.ParseGoMod(content, nil)
+		if err != nil {
+			return nil
+		}
+		f.packages = located(packages, location)
+	case apkDatabase:
+		f.packages = located(parseAPKDatabase(content), location)
+	case dpkgDatabase:
+		f.packages = located(parseDpkgDatabase(content), location)
+	}
+
+	return f
+}
+
+func located(packages []types.Package, location string) []types.Package {
+	for i := range packages {
+		packages[i].Location = location
+	}
+	return packages
+}
+
+// osRelease reads the distribution ID and release of the final filesystem
+func (fs *filesystem) osRelease() (string, string) {
+	for _, name := range []string{"etc/os-release", "usr/lib/os-release"} {
+		if f, ok := fs.files[name]; ok {
+			return parseOSRelease(f.content)
+		}
+	}
+	return "", ""
+}
+
+// packages lists the packages of the final filesystem with the layer that introduced them. OS
+// packages of distributions other than Alpine and Debian are counted as skipped.
+func (fs *filesystem) packages(distribution string, release string) ([]types.Package, int) {
+	names := make([]string, 0, len(fs.files))
+	for name := range fs.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var packages []types.Package
+	skipped := 0
+	for _, name := range names {
+		f := fs.files[name]
+
+		var ecosystem types.Ecosystem
+		switch {
+		case f.kind == apkDatabase && distribution == "alpine":
+			ecosystem = types.EcosystemAlpine
+		case f.kind == dpkgDatabase && distribution == "debian":
+			ecosystem = types.EcosystemDebian
+		case f.kind == apkDatabase || f.kind == dpkgDatabase:
+			skipped += len(f.packages)
+			continue
+		}
+
+		for _, pkg := range f.packages {
+			if ecosystem != "" {
+				pkg.Ecosystem = ecosystem
+				pkg.Release = release
+			}
+			packages = append(packages, pkg)
+		}
+	}
+
+	return packages, skipped
+}
@@ -0,0 +1,284 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"khazande/internal/types"
+)
+
+const (
+	// maxFileSize caps a file read into memory once decompressed, a layer of the tarball or a file
+	// of a layer
+	maxFileSize = 512 * 1024 * 1024
+	// maxTotalSize caps everything read into memory while unpacking an image, the tarball, its
+	// layers and the files of interest in them
+	maxTotalSize = 2 * 1024 * 1024 * 1024
+)
+
+// Image is what was found in the filesystem of a container image
+type Image struct {
+	// Layers are the diff IDs (or digests) of the layers, from the base one up
+	Layers []string
+	// OS and Release come from the os-release file, e.g. alpine and v3.18
+	OS       string
+	Release  string
+	Packages []types.Package
+	// SkippedOSPackages were installed by a package manager of a distribution no source covers
+	SkippedOSPackages int
+}
+
+// dockerManifest is an entry of the manifest.json of a `docker save` tarball
+type dockerManifest struct {
+	Config string   `json:"Config"`
+	Layers []string `json:"Layers"`
+}
+
+type imageConfig struct {
+	RootFS struct {
+		DiffIDs []string `json:"diff_ids"`
+	} `json:"rootfs"`
+}
+
+type ociDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+}
+
+// ociManifest is an OCI image index or image manifest, told apart by which of the lists is set
+type ociManifest struct {
+	Manifests []ociDescriptor `json:"manifests"`
+	Config    ociDescriptor   `json:"config"`
+	Layers    []ociDescriptor `json:"layers"`
+}
+
+// layer is a tarball of filesystem changes named by its diff ID
+type layer struct {
+	id      string
+	content []byte
+}
+
+// Read unpacks a `docker save` or OCI layout tarball, optionally gzipped, layer by layer and
+// collects the Go modules of the executables and go.mod files and the packages of the apk and
+// dpkg databases left in the final filesystem, each with the layer that introduced it
+func Read(content []byte) (*Image, error) {
+	budget := &sizeBudget{remaining: maxTotalSize}
+	files, err := readTar(content, budget)
+	if err != nil {
+		return nil, err
+	}
+
+	var layers []layer
+	switch {
+	case files["manifest.json"] != nil:
+		layers, err = dockerLayers(files)
+	case files["index.json"] != nil:
+		layers, err = ociLayers(files)
+	default:
+		return nil, fmt.Errorf("neither manifest.json nor index.json found, not a docker save or OCI layout tarball")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fs := newFilesystem(budget)
+	image := &Image{}
+	for _, l := range layers {
+		image.Layers = append(image.Layers, l.id)
+		if err := fs.apply(l); err != nil {
+			return nil, fmt.Errorf("failed to unpack layer %s: %v", l.id, err)
+		}
+	}
+
+	image.OS, image.Release = fs.osRelease()
+	image.Packages, image.SkippedOSPackages = fs.packages(image.OS, image.Release)
+
+	return image, nil
+}
+
+// dockerLayers lists the layers of the first image of a `docker save` tarball
+func dockerLayers(files map[string][]byte) ([]layer, error) {
+	var manifests []dockerManifest
+	if err := json.Unmarshal(files["manifest.json"], &manifests); err != nil {
+		return nil, fmt.Errorf("invalid manifest.json: %v", err)
+	}
+	if len(manifests) == 0 {
+		return nil, fmt.Errorf("manifest.json lists no image")
+	}
+	manifest := manifests[0]
+
+	var config imageConfig
+	if content, ok := files[cleanName(manifest.Config)]; ok {
+		if err := json.Unmarshal(content, &config); err != nil {
+			return nil, fmt.Errorf("invalid image config %s: %v", manifest.Config, err)
+		}
+	}
+
+	var layers []layer
+	for i, name := range manifest.Layers {
+		content, ok := files[cleanName(name)]
+		if !ok {
+			return nil, fmt.Errorf("layer %s is missing from the tarball", name)
+		}
+
+		id := name
+		if i < len(config.RootFS.DiffIDs) {
+			id = config.RootFS.DiffIDs[i]
+		}
+		layers = append(layers, layer{id: id, content: content})
+	}
+
+	return layers, nil
+}
+
+// ociLayers lists the layers of the first image of an OCI layout, following nested indexes of
+// multi-platform images
+func ociLayers(files map[string][]byte) ([]layer, error) {
+	var manifest ociManifest
+	if err := json.Unmarshal(files["index.json"], &manifest); err != nil {
+		return nil, fmt.Errorf("invalid index.json: %v", err)
+	}
+
+	for depth := 0; len(manifest.Manifests) != 0; depth++ {
+		if depth == 4 {
+			return nil, fmt.Errorf("too many nested image indexes")
+		}
+
+		content, err := ociBlob(files, manifest.Manifests[0].Digest)
+		if err != nil {
+			return nil, err
+		}
+		manifest = ociManifest{}
+		if err := json.Unmarshal(content, &manifest); err != nil {
+			return nil, fmt.Errorf("invalid image manifest: %v", err)
+		}
+	}
+
+	var config imageConfig
+	if content, err := ociBlob(files, manifest.Config.Digest); err == nil {
+		if err := json.Unmarshal(content, &config); err != nil {
+			return nil, fmt.Errorf("invalid image config: %v", err)
+		}
+	}
+
+	var layers []layer
+	for i, descriptor := range manifest.Layers {
+		content, err := ociBlob(files, descriptor.Digest)
+		if err != nil {
+			return nil, err
+		}
+
+		id := descriptor.Digest
+		if i < len(config.RootFS.DiffIDs) {
+			id = config.RootFS.DiffIDs[i]
+		}
+		layers = append(layers, layer{id: id, content: content})
+	}
+
+	return layers, nil
+}
+
+func ociBlob(files map[string][]byte, digest string) ([]byte, error) {
+	algorithm, hash, ok := strings.Cut(digest, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid digest %q", digest)
+	}
+
+	content, ok := files[path.Join("blobs", algorithm, hash)]
+	if !ok {
+		return nil, fmt.Errorf("blob %s is missing from the tarball", digest)
+	}
+	return content, nil
+}
+
+// readTar reads the regular files of a tarball, following the symbolic links `docker save` uses
+// for layers shared by several images
+func readTar(content []byte, budget *sizeBudget) (map[string][]byte, error) {
+	reader, err := decompress(content)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte)
+	links := make(map[string]string)
+
+	archive := tar.NewReader(reader)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar archive: %v", err)
+		}
+
+		name := cleanName(header.Name)
+		switch header.Typeflag {
+		case tar.TypeReg:
+			files[name], err = budget.read(name, archive)
+			if err != nil {
+				return nil, err
+			}
+		case tar.TypeSymlink:
+			links[name] = path.Join(path.Dir(name), header.Linkname)
+		case tar.TypeLink:
+			links[name] = cleanName(header.Linkname)
+		}
+	}
+
+	for name, target := range links {
+		if content, ok := files[target]; ok {
+			files[name] = content
+		}
+	}
+
+	return files, nil
+}
+
+// sizeBudget is what is left of maxTotalSize, compressed archives can expand far beyond the upload
+type sizeBudget struct {
+	remaining int64
+}
+
+// read reads a tar entry, failing once it exceeds maxFileSize or the remaining budget
+func (budget *sizeBudget) read(name string, reader io.Reader) ([]byte, error) {
+	limit := min(maxFileSize, budget.remaining)
+	content, err := io.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(content) > maxFileSize:
+		return nil, fmt.Errorf("%s is larger than %d MiB decompressed", name, maxFileSize>>20)
+	case int64(len(content)) > budget.remaining:
+		return nil, fmt.Errorf("the image is larger than %d MiB decompressed", maxTotalSize>>20)
+	}
+	budget.remaining -= int64(len(content))
+
+	return content, nil
+}
+
+// decompress reads gzipped content transparently, layers may or may not be compressed
+func decompress(content []byte) (io.Reader, error) {
+	if !bytes.HasPrefix(content, []byte{0x1f, 0x8b}) {
+		return bytes.NewReader(content), nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gzip archive: %v", err)
+	}
+	return reader, nil
+}
+
+// cleanName makes tar entry names comparable, without leading "./" or "/"
+func cleanName(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
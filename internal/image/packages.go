@@ -0,0 +1,115 @@
+package image
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+
+	distroModule "khazande/internal/distro"
+	"khazande/internal/types"
+)
+
+// parseAPKDatabase reads the packages of an apk installed database. Alpine advisories are about
+// the origin (source) package, a package built from it is reported under the origin name.
+func parseAPKDatabase(content []byte) []types.Package {
+	var packages []types.Package
+	seen := make(map[string]bool)
+
+	for _, stanza := range stanzas(content) {
+		name, version := stanza["o"], stanza["V"]
+		if name == "" {
+			name = stanza["P"]
+		}
+		if name == "" || version == "" || seen[name+"@"+version] {
+			continue
+		}
+		seen[name+"@"+version] = true
+		packages = append(packages, types.Package{Name: name, Version: version})
+	}
+
+	return packages
+}
+
+// parseDpkgDatabase reads the installed packages of a dpkg status file. Debian advisories are
+// about the source package, a binary package is reported under the source name and version.
+func parseDpkgDatabase(content []byte) []types.Package {
+	var packages []types.Package
+	seen := make(map[string]bool)
+
+	for _, stanza := range stanzas(content) {
+		// The status files of distroless images have no Status field
+		if status, ok := stanza["Status"]; ok && !strings.HasSuffix(status, " installed") {
+			continue
+		}
+
+		name, version := stanza["Package"], stanza["Version"]
+		if source := stanza["Source"]; source != "" {
+			// The source version is only given when it differs, e.g. "Source: glibc (2.36-9)"
+			sourceName, sourceVersion, ok := strings.Cut(source, " ")
+			name = sourceName
+			if ok {
+				version = strings.Trim(strings.TrimSpace(sourceVersion), "()")
+			}
+		}
+		if name == "" || version == "" || seen[name+"@"+version] {
+			continue
+		}
+		seen[name+"@"+version] = true
+		packages = append(packages, types.Package{Name: name, Version: version})
+	}
+
+	return packages
+}
+
+// parseOSRelease returns the ID of the distribution and its release as named by OSV, v3.18 for
+// Alpine 3.18.4 and the VERSION_ID otherwise
+func parseOSRelease(content []byte) (string, string) {
+	fields := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if ok {
+			fields[key] = strings.Trim(value, `"'`)
+		}
+	}
+
+	id, release := fields["ID"], fields["VERSION_ID"]
+	if id == "alpine" {
+		release = distroModule.AlpineRelease(release)
+	}
+
+	return id, release
+}
+
+// stanzas splits a database of "Key: value" or "K:value" lines into blank line separated records,
+// continuation lines of multiline values are skipped
+func stanzas(content []byte) []map[string]string {
+	var records []map[string]string
+	current := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			if len(current) != 0 {
+				records = append(records, current)
+				current = make(map[string]string)
+			}
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if ok {
+			current[key] = strings.TrimSpace(value)
+		}
+	}
+	if len(current) != 0 {
+		records = append(records, current)
+	}
+
+	return records
+}
@@ -3,18 +3,30 @@ package nvd
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
+	advisorModule "khazande/internal/advisor"
+	aggregatorModule "khazande/internal/aggregator"
+	cpeModule "khazande/internal/cpe"
 	crawlerModule "khazande/internal/crawler"
+	nvdapiModule "khazande/internal/nvdapi"
+	parserModule "khazande/internal/parser"
+	"khazande/internal/types"
+	envsModule "khazande/pkg/envs"
 	pb "khazande/pkg/grpc"
 
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type Server struct {
 	pb.UnimplementedScrapperServiceServer
 	Logger      *zap.Logger
 	RedisClient *redis.Client
+	Envs        *envsModule.Envs
+	Advisor     *advisorModule.Advisor
 }
 
 func handlePanic() {
@@ -31,15 +43,23 @@ func (s *Server) FetchVulnerabilities(ctx context.Context, req *pb.Vulnerability
 	query := req.GetName()
 	s.Logger.Info(fmt.Sprintf("Start searching for %s vulnerabilities", query))
 
-	crawler := crawlerModule.Crawler{Logger: s.Logger, RedisClient: s.RedisClient}
-
-	links := crawler.ExtractVulnerabilitiesLinks(query)
+	vulnerabilities, err := s.fetchVulnerabilities(query)
+	if err != nil {
+		return nil, err
+	}
 
-	if len(links) == 0 {
-		return nil, fmt.Errorf("there is no matching Vulnerabilities")
+	affected, err := versionFilter(req)
+	if err != nil {
+		return nil, err
 	}
 
-	vulnerabilities := crawler.ExtractVulnerabilitiesDetails(query, links)
+	var filtered []types.Vulnerability
+	for _, vulnerability := range vulnerabilities {
+		if affected(vulnerability) {
+			filtered = append(filtered, vulnerability)
+		}
+	}
+	vulnerabilities = filtered
 
 	if len(vulnerabilities) != 0 {
 		s.Logger.Info(fmt.Sprintf("Web Scrapper has extracted %d vulnerabilities successfully!", len(vulnerabilities)))
@@ -49,19 +69,300 @@ func (s *Server) FetchVulnerabilities(ctx context.Context, req *pb.Vulnerability
 
 	result := []*pb.Vulnerability{}
 	for _, vulnerability := range vulnerabilities {
-		result = append(result, &pb.Vulnerability{
-			Name:               vulnerability.Name,
-			CVEID:              vulnerability.CVEID,
-			PublishedDate:      vulnerability.PublishedDate,
-			LastModified:       vulnerability.LastModified,
-			Description:        vulnerability.Description,
-			VulnerableVersions: vulnerability.VulnerableVersions,
-			NVDScore:           vulnerability.NVDScore,
-			CNAScore:           vulnerability.CNAScore,
+		result = append(result, toProtoVulnerability(vulnerability))
+	}
+
+	return &pb.VulnerabilityResponse{
+		Vulnerabilities: result,
+	}, nil
+}
+
+// StreamVulnerabilities sends every vulnerability as soon as its details are extracted, interleaved
+// with progress messages, instead of waiting for the whole search like FetchVulnerabilities
+func (s *Server) StreamVulnerabilities(req *pb.VulnerabilityRequest, stream pb.ScrapperService_StreamVulnerabilitiesServer) error {
+	defer handlePanic()
+
+	query := req.GetName()
+	s.Logger.Info(fmt.Sprintf("Start streaming %s vulnerabilities", query))
+
+	affected, err := versionFilter(req)
+	if err != nil {
+		return err
+	}
+
+	progress := &pb.Progress{}
+	sent := 0
+	sendProgress := func() error {
+		return stream.Send(&pb.VulnerabilityStreamResponse{
+			Event: &pb.VulnerabilityStreamResponse_Progress{Progress: progress},
+		})
+	}
+	sendVulnerability := func(vulnerability types.Vulnerability) error {
+		if !affected(vulnerability) {
+			return nil
+		}
+		sent += 1
+		return stream.Send(&pb.VulnerabilityStreamResponse{
+			Event: &pb.VulnerabilityStreamResponse_Vulnerability{Vulnerability: toProtoVulnerability(vulnerability)},
 		})
 	}
 
+	client := nvdapiModule.Client{Logger: s.Logger, Envs: s.Envs, RedisClient: s.RedisClient}
+	err = client.Walk(query, nvdapiModule.Query{KeywordSearch: query}, func(page []types.Vulnerability, fetched int, total int) error {
+		// The API returns the details along with the search results
+		progress = &pb.Progress{PagesDiscovered: progress.PagesDiscovered + 1, DetailsDone: int32(fetched), DetailsTotal: int32(total)}
+		for _, vulnerability := range page {
+			if err := sendVulnerability(vulnerability); err != nil {
+				return err
+			}
+		}
+		return sendProgress()
+	})
+	if err == nil || sent != 0 || stream.Context().Err() != nil {
+		return err
+	}
+
+	s.Logger.Sugar().Errorf("NVD API failed for %s, falling back to the web crawler: %v", query, err)
+
+	crawler := crawlerModule.Crawler{Logger: s.Logger, RedisClient: s.RedisClient}
+
+	var sendErr error
+	links := crawler.ExtractVulnerabilitiesLinksWithProgress(query, func(pages int, links int) {
+		progress = &pb.Progress{PagesDiscovered: int32(pages), DetailsTotal: int32(links)}
+		if sendErr == nil {
+			sendErr = sendProgress()
+		}
+	})
+	if sendErr != nil {
+		return sendErr
+	}
+
+	if len(links) == 0 {
+		return fmt.Errorf("there is no matching Vulnerabilities")
+	}
+
+	// The crawler stops once the client is gone or a message can't be sent
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	for result := range crawler.StreamVulnerabilitiesDetails(ctx, query, links) {
+		progress = &pb.Progress{PagesDiscovered: progress.PagesDiscovered, DetailsDone: progress.DetailsDone + 1, DetailsTotal: progress.DetailsTotal, DetailsFailed: progress.DetailsFailed}
+		if result.Err != nil {
+			progress.DetailsFailed += 1
+		} else if err := sendVulnerability(result.Vulnerability); err != nil {
+			return err
+		}
+		if err := sendProgress(); err != nil {
+			return err
+		}
+	}
+
+	// Pages left out because the client went away leave the progress short
+	return stream.Context().Err()
+}
+
+// GetVulnerabilities looks the CVEs up by ID, serving the cached ones from Redis and fetching the
+// others concurrently. The response follows the order of the request, unknown IDs are left out.
+func (s *Server) GetVulnerabilities(ctx context.Context, req *pb.VulnerabilitiesByIDRequest) (*pb.VulnerabilityResponse, error) {
+	defer handlePanic()
+
+	var ids []string
+	seen := make(map[string]bool)
+	for _, id := range req.GetIds() {
+		id = strings.ToUpper(strings.TrimSpace(id))
+		if !types.IsCVEID(id) {
+			return nil, fmt.Errorf("%q is not a CVE ID", id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	aggregator := aggregatorModule.Aggregator{Logger: s.Logger, Envs: s.Envs, RedisClient: s.RedisClient}
+	found := aggregator.LookupCVEs(ids)
+
+	result := []*pb.Vulnerability{}
+	for _, id := range ids {
+		if vulnerability, ok := found[id]; ok {
+			result = append(result, toProtoVulnerability(vulnerability))
+		}
+	}
+
 	return &pb.VulnerabilityResponse{
 		Vulnerabilities: result,
 	}, nil
 }
+
+// FetchPackageVulnerabilities checks every package against the GitHub Advisory Database and returns
+// the findings of each package, in the order of the request
+func (s *Server) FetchPackageVulnerabilities(ctx context.Context, req *pb.PackageVulnerabilitiesRequest) (*pb.PackageVulnerabilitiesResponse, error) {
+	defer handlePanic()
+
+	var packages []types.Package
+	for _, requested := range req.GetPackages() {
+		if requested.GetName() == "" || requested.GetVersion() == "" {
+			return nil, fmt.Errorf("every package needs a name and a version")
+		}
+
+		ecosystem := types.EcosystemGo
+		if requested.GetEcosystem() != "" {
+			var err error
+			ecosystem, err = parserModule.ParseEcosystem(requested.GetEcosystem())
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		packages = append(packages, types.Package{Name: requested.GetName(), Version: requested.GetVersion(), Ecosystem: ecosystem})
+	}
+
+	s.Logger.Info(fmt.Sprintf("Start checking %d packages against the GitHub Advisory Database", len(packages)))
+	vulnerabilities := s.Advisor.FetchVulnerabilitiesFromGithub(packages)
+
+	response := &pb.PackageVulnerabilitiesResponse{}
+	for i, pkg := range packages {
+		findings := &pb.PackageFindings{Package: req.GetPackages()[i], Vulnerabilities: []*pb.Vulnerability{}}
+
+		// Findings are grouped by package, a package requested with several versions has them all
+		for _, vulnerability := range vulnerabilities[types.PackageKey(pkg.Ecosystem, pkg.Name)] {
+			if vulnerability.Version == pkg.Version && vulnerability.Ecosystem == pkg.Ecosystem {
+				findings.Vulnerabilities = append(findings.Vulnerabilities, toProtoVulnerability(*vulnerability))
+			}
+		}
+
+		response.Findings = append(response.Findings, findings)
+	}
+
+	return response, nil
+}
+
+// versionFilter reports whether the vulnerable CPE ranges of the requested product include the requested
+// version. Without a version every vulnerability matching the keyword is kept.
+func versionFilter(req *pb.VulnerabilityRequest) (func(types.Vulnerability) bool, error) {
+	if req.GetVersion() == "" {
+		return func(types.Vulnerability) bool { return true }, nil
+	}
+
+	var ecosystem types.Ecosystem
+	if req.GetEcosystem() != "" {
+		var err error
+		ecosystem, err = parserModule.ParseEcosystem(req.GetEcosystem())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return func(vulnerability types.Vulnerability) bool {
+		return cpeModule.Affects(vulnerability.CPEMatches, req.GetName(), req.GetVendor(), ecosystem, req.GetVersion())
+	}, nil
+}
+
+func toProtoVulnerability(vulnerability types.Vulnerability) *pb.Vulnerability {
+	result := &pb.Vulnerability{
+		Name:               vulnerability.Name,
+		CVEID:              vulnerability.CVEID,
+		PublishedDate:      toProtoTimestamp(vulnerability.PublishedDate),
+		LastModified:       toProtoTimestamp(vulnerability.LastModified),
+		Description:        vulnerability.Description,
+		VulnerableVersions: vulnerability.VulnerableVersions,
+		NVDScore:           toProtoScore(vulnerability.NVDScore),
+		CNAScore:           toProtoScore(vulnerability.CNAScore),
+		NVDVector:          vulnerability.NVDVector,
+		CNAVector:          vulnerability.CNAVector,
+		Summary:            vulnerability.Summary,
+		Severity:           toProtoSeverity(vulnerability.Severity),
+		AffectedVersions:   vulnerability.AffectedVersions,
+		PatchedVersions:    vulnerability.PatchedVersions,
+		GHSAID:             vulnerability.GHSAID,
+		Aliases:            vulnerability.Aliases,
+		Ecosystem:          string(vulnerability.Ecosystem),
+		Version:            vulnerability.Version,
+		Indirect:           vulnerability.Indirect,
+		References:         vulnerability.References,
+		Match:              vulnerability.Match,
+		MatchReason:        vulnerability.MatchReason,
+	}
+
+	for _, match := range vulnerability.CPEMatches {
+		result.CpeMatches = append(result.CpeMatches, &pb.CPEMatch{
+			Criteria:              match.Criteria,
+			Vendor:                match.Vendor,
+			Product:               match.Product,
+			Version:               match.Version,
+			VersionStartIncluding: match.VersionStartIncluding,
+			VersionStartExcluding: match.VersionStartExcluding,
+			VersionEndIncluding:   match.VersionEndIncluding,
+			VersionEndExcluding:   match.VersionEndExcluding,
+			Vulnerable:            match.Vulnerable,
+		})
+	}
+
+	return result
+}
+
+// toProtoTimestamp leaves the timestamp unset when the date is missing or unparsable
+func toProtoTimestamp(date string) *timestamppb.Timestamp {
+	parsed, err := types.ParseDate(date)
+	if err != nil {
+		return nil
+	}
+
+	return timestamppb.New(parsed)
+}
+
+// toProtoScore reads the base score out of scores like "7.5 HIGH"
+func toProtoScore(score string) float64 {
+	fields := strings.Fields(score)
+	if len(fields) == 0 {
+		return 0
+	}
+
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0
+	}
+
+	return value
+}
+
+// toProtoSeverity maps the GitHub severities and the CVSS ones reported by NVD
+func toProtoSeverity(severity string) pb.Severity {
+	switch strings.ToUpper(severity) {
+	case "LOW":
+		return pb.Severity_SEVERITY_LOW
+	case "MODERATE", "MEDIUM":
+		return pb.Severity_SEVERITY_MODERATE
+	case "HIGH":
+		return pb.Severity_SEVERITY_HIGH
+	case "CRITICAL":
+		return pb.Severity_SEVERITY_CRITICAL
+	default:
+		return pb.Severity_SEVERITY_UNSPECIFIED
+	}
+}
+
+// fetchVulnerabilities asks the NVD CVE API first and only scrapes the NVD website when the API fails
+func (s *Server) fetchVulnerabilities(query string) ([]types.Vulnerability, error) {
+	client := nvdapiModule.Client{Logger: s.Logger, Envs: s.Envs, RedisClient: s.RedisClient}
+
+	vulnerabilities, err := client.FetchVulnerabilities(query, nvdapiModule.Query{KeywordSearch: query})
+	if err == nil {
+		if len(vulnerabilities) == 0 {
+			return nil, fmt.Errorf("there is no matching Vulnerabilities")
+		}
+		return vulnerabilities, nil
+	}
+
+	s.Logger.Sugar().Errorf("NVD API failed for %s, falling back to the web crawler: %v", query, err)
+
+	crawler := crawlerModule.Crawler{Logger: s.Logger, RedisClient: s.RedisClient}
+
+	links := crawler.ExtractVulnerabilitiesLinks(query)
+
+	if len(links) == 0 {
+		return nil, fmt.Errorf("there is no matching Vulnerabilities")
+	}
+
+	return crawler.ExtractVulnerabilitiesDetails(query, links), nil
+}
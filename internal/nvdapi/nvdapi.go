@@ -0,0 +1,264 @@
+package nvdapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	cpeModule "khazande/internal/cpe"
+	"khazande/internal/types"
+	envsModule "khazande/pkg/envs"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultURL = "https://services.nvd.nist.gov/rest/json/cves/2.0"
+	// maxResultsPerPage is the largest page the CVE API 2.0 serves
+	maxResultsPerPage = 2000
+)
+
+var (
+	throttleMutex sync.Mutex
+	// nextRequest is when the next request may be sent, shared by every client so that concurrent
+	// lookups stay under the rate limit together
+	nextRequest time.Time
+)
+
+// Client talks to the NVD CVE API 2.0, see https://nvd.nist.gov/developers/vulnerabilities
+type Client struct {
+	Logger      *zap.Logger
+	Envs        *envsModule.Envs
+	RedisClient *redis.Client
+}
+
+// Query holds the supported parameters of the CVE API. Empty values are not sent.
+type Query struct {
+	KeywordSearch  string
+	CPEName        string
+	CVEID          string
+	StartIndex     int
+	ResultsPerPage int
+}
+
+func (q Query) values() url.Values {
+	values := url.Values{}
+	if q.KeywordSearch != "" {
+		values.Set("keywordSearch", q.KeywordSearch)
+	}
+	if q.CPEName != "" {
+		values.Set("cpeName", q.CPEName)
+	}
+	if q.CVEID != "" {
+		values.Set("cveId", q.CVEID)
+	}
+	if q.StartIndex > 0 {
+		values.Set("startIndex", strconv.Itoa(q.StartIndex))
+	}
+	if q.ResultsPerPage > 0 {
+		values.Set("resultsPerPage", strconv.Itoa(q.ResultsPerPage))
+	}
+	return values
+}
+
+// Search requests a single page of results
+func (client *Client) Search(query Query) (*types.NVDResponse, error) {
+	baseURL := client.Envs.NVD_API_URL
+	if baseURL == "" {
+		baseURL = defaultURL
+	}
+
+	client.throttle()
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s?%s", baseURL, query.values().Encode()), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	if client.Envs.NVD_API_KEY != "" {
+		req.Header.Set("apiKey", client.Envs.NVD_API_KEY)
+	}
+
+	httpClient := &http.Client{Timeout: time.Minute}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to perform request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// The API explains rejected requests in the message header
+		return nil, fmt.Errorf("NVD API responded with %s: %s", resp.Status, resp.Header.Get("message"))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	var nvdResponse types.NVDResponse
+	if err := json.Unmarshal(body, &nvdResponse); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+
+	return &nvdResponse, nil
+}
+
+// FetchVulnerabilities pages through every result of the query starting at query.StartIndex. The
+// vulnerabilities are named after name, like the ones scraped by the crawler, and cached by CVE ID.
+func (client *Client) FetchVulnerabilities(name string, query Query) ([]types.Vulnerability, error) {
+	var vulnerabilities []types.Vulnerability
+
+	err := client.Walk(name, query, func(page []types.Vulnerability, fetched int, total int) error {
+		vulnerabilities = append(vulnerabilities, page...)
+		return nil
+	})
+
+	return vulnerabilities, err
+}
+
+// Walk is FetchVulnerabilities calling fn after every page with the vulnerabilities of the page and
+// the number of results fetched so far out of the total. An error returned by fn stops the walk.
+func (client *Client) Walk(name string, query Query, fn func(page []types.Vulnerability, fetched int, total int) error) error {
+	if query.ResultsPerPage == 0 {
+		query.ResultsPerPage = maxResultsPerPage
+	}
+
+	for {
+		nvdResponse, err := client.Search(query)
+		if err != nil {
+			return err
+		}
+
+		var page []types.Vulnerability
+		for _, item := range nvdResponse.Vulnerabilities {
+			vulnerability := ToVulnerability(name, item.CVE)
+			client.cache(vulnerability)
+			page = append(page, vulnerability)
+		}
+
+		query.StartIndex = nvdResponse.StartIndex + len(nvdResponse.Vulnerabilities)
+		if err := fn(page, query.StartIndex, nvdResponse.TotalResults); err != nil {
+			return err
+		}
+
+		if len(nvdResponse.Vulnerabilities) == 0 || query.StartIndex >= nvdResponse.TotalResults {
+			return nil
+		}
+
+		client.Logger.Info(fmt.Sprintf("NVD API returned %d of %d vulnerabilities for %s", query.StartIndex, nvdResponse.TotalResults, name))
+	}
+}
+
+// GetVulnerability fetches a single CVE by its ID, the vulnerability is not named after any search
+func (client *Client) GetVulnerability(cveID string) (types.Vulnerability, error) {
+	nvdResponse, err := client.Search(Query{CVEID: cveID})
+	if err != nil {
+		return types.Vulnerability{}, err
+	}
+
+	if len(nvdResponse.Vulnerabilities) == 0 {
+		return types.Vulnerability{}, fmt.Errorf("%s is not known to NVD", cveID)
+	}
+
+	vulnerability := ToVulnerability("", nvdResponse.Vulnerabilities[0].CVE)
+	client.cache(vulnerability)
+
+	return vulnerability, nil
+}
+
+// throttle keeps the clients under the public rate limits: 5 requests per 30 seconds without an
+// API key and 50 with one. Every request reserves the next free slot and waits for it.
+func (client *Client) throttle() {
+	interval := 6 * time.Second
+	if client.Envs.NVD_API_KEY != "" {
+		interval = 600 * time.Millisecond
+	}
+
+	throttleMutex.Lock()
+	now := time.Now()
+	slot := nextRequest
+	if slot.Before(now) {
+		slot = now
+	}
+	nextRequest = slot.Add(interval)
+	throttleMutex.Unlock()
+
+	time.Sleep(time.Until(slot))
+}
+
+func (client *Client) cache(vulnerability types.Vulnerability) {
+	if client.RedisClient == nil || vulnerability.CVEID == "" {
+		return
+	}
+
+	jsonVulnerability, marshalErr := json.Marshal(vulnerability)
+	if marshalErr == nil {
+		client.RedisClient.Set(context.Background(), vulnerability.CVEID, jsonVulnerability, 72*time.Hour)
+	}
+}
+
+// ToVulnerability converts a CVE of the API into the record the crawler extracts from the detail page
+func ToVulnerability(name string, cve types.NVDCVE) types.Vulnerability {
+	vulnerability := types.Vulnerability{
+		Name:          name,
+		CVEID:         cve.ID,
+		PublishedDate: cve.Published,
+		LastModified:  cve.LastModified,
+		Sources:       []string{types.SourceNVD},
+	}
+
+	for _, description := range cve.Descriptions {
+		if description.Lang == "en" {
+			vulnerability.Description = description.Value
+		}
+	}
+
+	// Primary metrics come from NVD itself, secondary ones from the CNA that published the CVE.
+	// CVSS v3.1 and v3.0 are preferred, v4.0 is only used by newer CNAs and v2 by older CVEs.
+	var metrics []types.NVDCVSSMetric
+	for _, candidates := range [][]types.NVDCVSSMetric{cve.Metrics.CvssMetricV31, cve.Metrics.CvssMetricV30, cve.Metrics.CvssMetricV40, cve.Metrics.CvssMetricV2} {
+		if len(candidates) != 0 {
+			metrics = candidates
+			break
+		}
+	}
+	for _, metric := range metrics {
+		score := fmt.Sprintf("%.1f %s", metric.CvssData.BaseScore, metric.Severity())
+		if metric.Type == "Primary" {
+			vulnerability.NVDScore = score
+			vulnerability.NVDVector = metric.CvssData.VectorString
+			vulnerability.Severity = metric.Severity()
+		} else if vulnerability.CNAScore == "" {
+			vulnerability.CNAScore = score
+			vulnerability.CNAVector = metric.CvssData.VectorString
+		}
+	}
+	if vulnerability.Severity == "" && len(metrics) != 0 {
+		vulnerability.Severity = metrics[0].Severity()
+	}
+
+	for _, configuration := range cve.Configurations {
+		for _, node := range configuration.Nodes {
+			for _, match := range node.CPEMatch {
+				record := cpeModule.FromNVD(match)
+				vulnerability.CPEMatches = append(vulnerability.CPEMatches, record)
+				if record.Vulnerable {
+					vulnerability.VulnerableVersions = append(vulnerability.VulnerableVersions, cpeModule.Describe(record))
+				}
+			}
+		}
+	}
+
+	for _, reference := range cve.References {
+		vulnerability.References = append(vulnerability.References, reference.URL)
+	}
+
+	return vulnerability
+}
@@ -0,0 +1,178 @@
+package osv
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"khazande/internal/types"
+	envsModule "khazande/pkg/envs"
+
+	"go.uber.org/zap"
+)
+
+const defaultAPIURL = "https://api.osv.dev/v1"
+
+// Client checks packages against the local database of OSV_PATH when it is set and against the
+// OSV API (https://google.github.io/osv.dev/api/) otherwise
+type Client struct {
+	Logger *zap.Logger
+	Envs   *envsModule.Envs
+
+	once     sync.Once
+	database *Database
+	loadErr  error
+}
+
+type apiQuery struct {
+	Package   Package `json:"package"`
+	Version   string  `json:"version"`
+	PageToken string  `json:"page_token,omitempty"`
+}
+
+type apiResponse struct {
+	Vulns         []Entry `json:"vulns"`
+	NextPageToken string  `json:"next_page_token"`
+}
+
+// FetchVulnerabilities returns the vulnerabilities of the packages keyed by types.PackageKey, like
+// Advisor.FetchVulnerabilitiesFromGithub
+func (client *Client) FetchVulnerabilities(packages []types.Package) map[string][]*types.Vulnerability {
+	vulnerabilities := make(map[string][]*types.Vulnerability)
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+
+	for _, pkg := range packages {
+		if pkg.Ecosystem == "" {
+			pkg.Ecosystem = types.EcosystemGo
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			packageVulnerabilities, err := client.fetchPackageVulnerabilities(pkg)
+			if err != nil {
+				client.Logger.Sugar().Errorf("Failed to fetch OSV vulnerabilities of %s: %v", pkg.Name, err)
+				return
+			}
+
+			mutex.Lock()
+			key := types.PackageKey(pkg.Ecosystem, pkg.Name)
+			vulnerabilities[key] = append(vulnerabilities[key], packageVulnerabilities...)
+			mutex.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	return vulnerabilities
+}
+
+func (client *Client) fetchPackageVulnerabilities(pkg types.Package) ([]*types.Vulnerability, error) {
+	if client.Envs.OSV_PATH == "" {
+		return client.queryAPI(pkg)
+	}
+
+	database, err := client.Database()
+	if err != nil {
+		return nil, err
+	}
+
+	return MatchEntries(database.Entries(pkg.Ecosystem, pkg.Name), pkg, client.Logger), nil
+}
+
+// Database loads the database of OSV_PATH on first use
+func (client *Client) Database() (*Database, error) {
+	client.once.Do(func() {
+		client.database, client.loadErr = Load(client.Envs.OSV_PATH)
+		if client.loadErr == nil {
+			client.Logger.Info(fmt.Sprintf("Loaded %d OSV entries from %s", client.database.Len(), client.Envs.OSV_PATH))
+		}
+	})
+
+	return client.database, client.loadErr
+}
+
+// MatchEntries converts the entries whose ranges include the version of the package. Entries whose
+// ranges couldn't be checked are converted as undetermined.
+func MatchEntries(entries []*Entry, pkg types.Package, logger *zap.Logger) []*types.Vulnerability {
+	var result []*types.Vulnerability
+	for _, entry := range entries {
+		matched, versionRange, fixed, err := entry.Match(pkg)
+		switch {
+		case matched:
+			result = append(result, entry.ToVulnerability(pkg, versionRange, fixed))
+		case err != nil:
+			logger.Sugar().Errorf("Error checking version range of %s: %v", entry.ID, err)
+			vulnerability := entry.ToVulnerability(pkg, "", "")
+			vulnerability.Match = types.MatchUnknown
+			vulnerability.MatchReason = fmt.Sprintf("checking %s against the ranges of %s failed: %v", pkg.Version, entry.ID, err)
+			result = append(result, vulnerability)
+		}
+	}
+	return result
+}
+
+// queryAPI pages through the vulnerabilities the API reports for the version of the package
+func (client *Client) queryAPI(pkg types.Package) ([]*types.Vulnerability, error) {
+	baseURL := client.Envs.OSV_API_URL
+	if baseURL == "" {
+		baseURL = defaultAPIURL
+	}
+
+	query := apiQuery{
+		Package: Package{Ecosystem: EcosystemName(pkg.Ecosystem), Name: pkg.Name},
+		Version: pkg.Version,
+	}
+	// OS packages are fixed release by release, e.g. "Debian:12"
+	if pkg.Release != "" {
+		query.Package.Ecosystem += ":" + pkg.Release
+	}
+	if pkg.Ecosystem == types.EcosystemGo {
+		query.Version = trimV(pkg.Version)
+	}
+
+	httpClient := &http.Client{Timeout: time.Minute}
+	var result []*types.Vulnerability
+
+	for {
+		body, err := json.Marshal(query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal query: %v", err)
+		}
+
+		resp, err := httpClient.Post(baseURL+"/query", "application/json", bytes.NewBuffer(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to perform request: %v", err)
+		}
+		content, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("OSV API responded with %s: %s", resp.Status, content)
+		}
+
+		var response apiResponse
+		if err := json.Unmarshal(content, &response); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+		}
+
+		for i := range response.Vulns {
+			entry := &response.Vulns[i]
+			// The API already matched the version, the range is only looked up for the report
+			_, versionRange, fixed, _ := entry.Match(pkg)
+			result = append(result, entry.ToVulnerability(pkg, versionRange, fixed))
+		}
+
+		if response.NextPageToken == "" {
+			return result, nil
+		}
+		query.PageToken = response.NextPageToken
+	}
+}
@@ -0,0 +1,137 @@
+package osv
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"khazande/internal/types"
+)
+
+// Database is an offline set of OSV entries indexed by the packages they affect
+type Database struct {
+	entries map[types.Ecosystem]map[string][]*Entry
+	size    int
+}
+
+// Load reads the OSV entries of a directory, walked recursively, or of a zip export such as
+// https://osv-vulnerabilities.storage.googleapis.com/Go/all.zip. Every .json file is an entry.
+func Load(path string) (*Database, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &Database{entries: make(map[types.Ecosystem]map[string][]*Entry)}
+
+	if info.IsDir() {
+		err = filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(file, ".json") {
+				return err
+			}
+
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			return db.add(file, content)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	}
+
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s is neither a directory nor a zip file: %v", path, err)
+	}
+	defer archive.Close()
+
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !strings.HasSuffix(file.Name, ".json") {
+			continue
+		}
+
+		reader, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := db.add(file.Name, content); err != nil {
+			return nil, err
+		}
+	}
+
+	return db, nil
+}
+
+func (db *Database) add(file string, content []byte) error {
+	var entry Entry
+	if err := json.Unmarshal(content, &entry); err != nil {
+		return fmt.Errorf("failed to parse %s: %v", file, err)
+	}
+
+	// Other JSON documents, e.g. the index of a vulndb, have no ID
+	if entry.ID == "" || entry.Withdrawn != "" {
+		return nil
+	}
+
+	db.Add(&entry)
+	return nil
+}
+
+// Add indexes the entry under every package it affects
+func (db *Database) Add(entry *Entry) {
+	if db.entries == nil {
+		db.entries = make(map[types.Ecosystem]map[string][]*Entry)
+	}
+
+	indexed := make(map[string]bool)
+	for _, affected := range entry.Affected {
+		ecosystem, ok := Ecosystem(affected.Package.Ecosystem)
+		if !ok {
+			continue
+		}
+
+		key := packageKey(ecosystem, affected.Package.Name)
+		if indexed[string(ecosystem)+key] {
+			continue
+		}
+		indexed[string(ecosystem)+key] = true
+
+		if db.entries[ecosystem] == nil {
+			db.entries[ecosystem] = make(map[string][]*Entry)
+		}
+		db.entries[ecosystem][key] = append(db.entries[ecosystem][key], entry)
+	}
+	db.size += 1
+}
+
+// Len is the number of entries of the database
+func (db *Database) Len() int {
+	return db.size
+}
+
+// Entries returns the entries affecting the package, whatever its version
+func (db *Database) Entries(ecosystem types.Ecosystem, name string) []*Entry {
+	return db.entries[ecosystem][packageKey(ecosystem, name)]
+}
+
+// packageKey normalizes PyPI names, they are case and separator insensitive
+func packageKey(ecosystem types.Ecosystem, name string) string {
+	if ecosystem == types.EcosystemPip {
+		return strings.ToLower(strings.NewReplacer("_", "-", ".", "-").Replace(name))
+	}
+	return name
+}
@@ -0,0 +1,296 @@
+package osv
+
+import (
+	"strings"
+
+	"khazande/internal/types"
+	versionsModule "khazande/internal/versions"
+)
+
+// Entry is an advisory in the Open Source Vulnerability format, see https://ossf.github.io/osv-schema/
+type Entry struct {
+	ID               string      `json:"id"`
+	Modified         string      `json:"modified"`
+	Published        string      `json:"published"`
+	Withdrawn        string      `json:"withdrawn"`
+	Aliases          []string    `json:"aliases"`
+	Summary          string      `json:"summary"`
+	Details          string      `json:"details"`
+	Severity         []Severity  `json:"severity"`
+	Affected         []Affected  `json:"affected"`
+	References       []Reference `json:"references"`
+	DatabaseSpecific struct {
+		// Set by the GitHub Advisory Database, e.g. MODERATE
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+type Severity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+type Affected struct {
+	Package           Package  `json:"package"`
+	Ranges            []Range  `json:"ranges"`
+	Versions          []string `json:"versions"`
+	EcosystemSpecific struct {
+		// Set by the Go vulndb
+		Imports []Import `json:"imports"`
+	} `json:"ecosystem_specific"`
+}
+
+// Import is a vulnerable package of a Go module with its vulnerable symbols
+type Import struct {
+	Path    string   `json:"path"`
+	Symbols []string `json:"symbols"`
+	GOOS    []string `json:"goos"`
+	GOARCH  []string `json:"goarch"`
+}
+
+type Package struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	Purl      string `json:"purl"`
+}
+
+type Range struct {
+	Type   string  `json:"type"`
+	Repo   string  `json:"repo"`
+	Events []Event `json:"events"`
+}
+
+// Event is one of introduced, fixed, last_affected or limit
+type Event struct {
+	Introduced   string `json:"introduced"`
+	Fixed        string `json:"fixed"`
+	LastAffected string `json:"last_affected"`
+	Limit        string `json:"limit"`
+}
+
+type Reference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// Range types of the schema
+const (
+	RangeSemver    = "SEMVER"
+	RangeEcosystem = "ECOSYSTEM"
+	RangeGit       = "GIT"
+)
+
+// OSV names of the ecosystems the advisory sources cover
+var ecosystems = map[string]types.Ecosystem{
+	"Go":        types.EcosystemGo,
+	"npm":       types.EcosystemNpm,
+	"PyPI":      types.EcosystemPip,
+	"Maven":     types.EcosystemMaven,
+	"RubyGems":  types.EcosystemRubyGems,
+	"NuGet":     types.EcosystemNuGet,
+	"crates.io": types.EcosystemRust,
+	"Packagist": types.EcosystemComposer,
+	"Pub":       types.EcosystemPub,
+	"Alpine":    types.EcosystemAlpine,
+	"Debian":    types.EcosystemDebian,
+}
+
+// Ecosystem maps an OSV ecosystem such as "crates.io" or "Debian:12" to ours, ignoring the release suffix
+func Ecosystem(name string) (types.Ecosystem, bool) {
+	name, _, _ = strings.Cut(name, ":")
+	ecosystem, ok := ecosystems[name]
+	return ecosystem, ok
+}
+
+// EcosystemName is the OSV name of the ecosystem
+func EcosystemName(ecosystem types.Ecosystem) string {
+	for name, e := range ecosystems {
+		if e == ecosystem {
+			return name
+		}
+	}
+	return ""
+}
+
+// Match looks for the affected entry of the package and returns the range the version falls in,
+// written like a GitHub vulnerableVersionRange (e.g. ">= 1.0.0, < 1.2.3"), and the version fixing it.
+// A range that can't be checked is only reported when no other affected entry matches.
+func (entry *Entry) Match(pkg types.Package) (matched bool, versionRange string, fixed string, err error) {
+	for _, affected := range entry.Affected {
+		ecosystem, ok := Ecosystem(affected.Package.Ecosystem)
+		if !ok || ecosystem != pkg.Ecosystem || !samePackage(ecosystem, affected.Package.Name, pkg.Name) {
+			continue
+		}
+		if !sameRelease(affected.Package.Ecosystem, pkg.Release) {
+			continue
+		}
+
+		matched, versionRange, fixed, matchErr := affected.match(ecosystem, pkg.Version)
+		if matched {
+			return matched, versionRange, fixed, nil
+		}
+		// Another block of the package may still match, the error only counts if none does
+		if matchErr != nil {
+			err = matchErr
+		}
+	}
+
+	return false, "", "", err
+}
+
+func (affected Affected) match(ecosystem types.Ecosystem, version string) (bool, string, string, error) {
+	for _, listed := range affected.Versions {
+		if trimV(listed) == trimV(version) {
+			return true, "= " + listed, "", nil
+		}
+	}
+
+	var rangeErr error
+	for _, r := range affected.Ranges {
+		switch r.Type {
+		case RangeSemver, RangeEcosystem:
+			for _, interval := range r.intervals() {
+				inRange, err := versionsModule.InRange(ecosystem, version, interval.versionRange)
+				if err != nil {
+					rangeErr = err
+					continue
+				}
+				if inRange {
+					return true, interval.versionRange, interval.fixed, nil
+				}
+			}
+		case RangeGit:
+			// Commits can't be ordered without the repository, only the listed versions and
+			// the commits themselves are matched
+			for _, event := range r.Events {
+				if commit := event.Introduced; commit != "" && commit != "0" && len(version) >= 7 && strings.HasPrefix(commit, version) {
+					return true, "= " + commit, "", nil
+				}
+			}
+		}
+	}
+
+	return false, "", "", rangeErr
+}
+
+type interval struct {
+	versionRange string
+	fixed        string
+}
+
+// intervals pairs every introduced event with the fixed or last_affected event that follows it.
+// An introduced version of "0" means every version before the end of the interval.
+func (r Range) intervals() []interval {
+	var intervals []interval
+	introduced := ""
+	open := false
+
+	closeWith := func(clause string, fixed string) {
+		var clauses []string
+		if introduced != "" && introduced != "0" {
+			clauses = append(clauses, ">= "+introduced)
+		}
+		if clause != "" {
+			clauses = append(clauses, clause)
+		}
+		if len(clauses) == 0 {
+			// Every version is affected
+			clauses = append(clauses, ">= 0")
+		}
+		intervals = append(intervals, interval{versionRange: strings.Join(clauses, ", "), fixed: fixed})
+		open = false
+	}
+
+	for _, event := range r.Events {
+		switch {
+		case event.Introduced != "":
+			if open {
+				closeWith("", "")
+			}
+			introduced, open = event.Introduced, true
+		case event.Fixed != "" && open:
+			closeWith("< "+event.Fixed, event.Fixed)
+		case event.LastAffected != "" && open:
+			closeWith("<= "+event.LastAffected, "")
+		}
+	}
+	if open {
+		closeWith("", "")
+	}
+
+	return intervals
+}
+
+// ToVulnerability converts an entry matching the package, as the advisor does with GitHub advisories
+func (entry *Entry) ToVulnerability(pkg types.Package, versionRange string, fixed string) *types.Vulnerability {
+	vulnerability := &types.Vulnerability{
+		Name:             pkg.Name,
+		Ecosystem:        pkg.Ecosystem,
+		Version:          pkg.Version,
+		Indirect:         pkg.Indirect,
+		Line:             pkg.Line,
+		BomRef:           pkg.BomRef,
+		Layer:            pkg.Layer,
+		Location:         pkg.Location,
+		Summary:          entry.Summary,
+		Description:      entry.Details,
+		PublishedDate:    entry.Published,
+		LastModified:     entry.Modified,
+		AffectedVersions: versionRange,
+		PatchedVersions:  fixed,
+		Severity:         strings.ToUpper(entry.DatabaseSpecific.Severity),
+		Sources:          []string{types.SourceOSV},
+		Match:            types.MatchAffected,
+	}
+
+	for _, id := range append([]string{entry.ID}, entry.Aliases...) {
+		switch {
+		case strings.HasPrefix(id, "GHSA-") && vulnerability.GHSAID == "":
+			vulnerability.GHSAID = id
+			continue
+		case strings.HasPrefix(id, "CVE-") && vulnerability.CVEID == "":
+			vulnerability.CVEID = id
+		}
+		vulnerability.Aliases = append(vulnerability.Aliases, id)
+	}
+
+	for _, severity := range entry.Severity {
+		// The schema only carries the vector, not the base score
+		if strings.HasPrefix(severity.Type, "CVSS_V3") && vulnerability.NVDVector == "" {
+			vulnerability.NVDVector = severity.Score
+		}
+	}
+
+	for _, reference := range entry.References {
+		vulnerability.References = append(vulnerability.References, reference.URL)
+	}
+
+	for _, affected := range entry.Affected {
+		ecosystem, ok := Ecosystem(affected.Package.Ecosystem)
+		if !ok || ecosystem != pkg.Ecosystem || !samePackage(ecosystem, affected.Package.Name, pkg.Name) {
+			continue
+		}
+		for _, imported := range affected.EcosystemSpecific.Imports {
+			vulnerability.Imports = append(vulnerability.Imports, types.VulnerableImport{Path: imported.Path, Symbols: imported.Symbols})
+		}
+	}
+
+	return vulnerability
+}
+
+// samePackage compares names the way the ecosystem does, PyPI names are case and separator insensitive
+func samePackage(ecosystem types.Ecosystem, a string, b string) bool {
+	return packageKey(ecosystem, a) == packageKey(ecosystem, b)
+}
+
+// sameRelease tells whether an OSV ecosystem such as "Alpine:v3.18" covers the release of an OS
+// package. Ecosystems without a release suffix and packages without a release match any.
+func sameRelease(name string, release string) bool {
+	_, suffix, ok := strings.Cut(name, ":")
+	return !ok || release == "" || suffix == release
+}
+
+// trimV drops the v prefix of Go versions, OSV writes them without it
+func trimV(version string) string {
+	return strings.TrimPrefix(version, "v")
+}
@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	types "khazande/internal/types"
+)
+
+type goListModule struct {
+	Path      string        `json:"Path"`
+	Version   string        `json:"Version"`
+	Main      bool          `json:"Main"`
+	Indirect  bool          `json:"Indirect"`
+	Replace   *goListModule `json:"Replace"`
+	GoVersion string        `json:"GoVersion"`
+}
+
+// ParseGoList reads the stream of JSON objects printed by `go list -m -json all`. The main module
+// is skipped, its go version is added as the stdlib and toolchain modules, and replaced modules are
+// resolved to their replacement.
+func ParseGoList(content []byte) ([]types.Package, error) {
+	var packages []types.Package
+	decoder := json.NewDecoder(bytes.NewReader(content))
+
+	for {
+		var mod goListModule
+		if err := decoder.Decode(&mod); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid go list -m -json output: %v", err)
+		}
+
+		if mod.Main {
+			packages = append(packages, goReleasePackages(mod.GoVersion, nil)...)
+			continue
+		}
+
+		effective := mod
+		if mod.Replace != nil {
+			effective = *mod.Replace
+		}
+		// Modules replaced by a local directory have no version to check
+		if effective.Version == "" {
+			continue
+		}
+
+		packages = append(packages, types.Package{
+			Name:      effective.Path,
+			Version:   effective.Version,
+			Ecosystem: types.EcosystemGo,
+			Indirect:  mod.Indirect,
+		})
+	}
+
+	return packages, nil
+}
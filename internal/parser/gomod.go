@@ -0,0 +1,150 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	types "khazande/internal/types"
+	versionsModule "khazande/internal/versions"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// ParseGoMod extracts the modules required by a go.mod. Replaced modules are resolved to their
+// effective path and version and excluded versions are dropped. When a go.sum is given, the
+// modules it lists that go.mod doesn't require are added as indirect dependencies. The Go release
+// of the toolchain or go directive is added as the stdlib and toolchain modules.
+func ParseGoMod(goMod []byte, goSum []byte) ([]types.Package, error) {
+	file, err := modfile.Parse("go.mod", goMod, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid go.mod: %v", err)
+	}
+
+	excluded := make(map[module.Version]bool)
+	for _, exclude := range file.Exclude {
+		excluded[exclude.Mod] = true
+	}
+
+	var packages []types.Package
+	required := make(map[string]bool)
+
+	// The toolchain directive names the release in use, the go directive only the minimum one
+	switch {
+	case file.Toolchain != nil:
+		packages = append(packages, goReleasePackages(file.Toolchain.Name, file.Toolchain.Syntax)...)
+	case file.Go != nil:
+		packages = append(packages, goReleasePackages(file.Go.Version, file.Go.Syntax)...)
+	}
+
+	for _, require := range file.Require {
+		required[require.Mod.Path] = true
+		if excluded[require.Mod] {
+			continue
+		}
+
+		if pkg, ok := resolveReplacement(file, require.Mod); ok {
+			pkg.Indirect = require.Indirect
+			if require.Syntax != nil {
+				pkg.Line = require.Syntax.Start.Line
+			}
+			packages = append(packages, pkg)
+		}
+	}
+
+	if len(goSum) == 0 {
+		return packages, nil
+	}
+
+	for _, mod := range parseGoSum(goSum) {
+		if required[mod.Path] || excluded[mod] {
+			continue
+		}
+
+		if pkg, ok := resolveReplacement(file, mod); ok {
+			pkg.Indirect = true
+			packages = append(packages, pkg)
+		}
+	}
+
+	return packages, nil
+}
+
+// goReleasePackages are the standard library and the toolchain of a Go release, as named by the
+// Go vulndb. An unknown release such as "default" gives none.
+func goReleasePackages(release string, syntax *modfile.Line) []types.Package {
+	version, ok := versionsModule.GoSemver(release)
+	if !ok {
+		return nil
+	}
+
+	line := 0
+	if syntax != nil {
+		line = syntax.Start.Line
+	}
+
+	return []types.Package{
+		{Name: types.GoStdlib, Version: version, Ecosystem: types.EcosystemGo, Line: line},
+		{Name: types.GoToolchain, Version: version, Ecosystem: types.EcosystemGo, Line: line},
+	}
+}
+
+// resolveReplacement applies the replace directives of the go.mod to the module. Replacements by
+// a local directory have no version to check and are reported as not resolvable.
+func resolveReplacement(file *modfile.File, mod module.Version) (types.Package, bool) {
+	effective := mod
+
+	for _, replace := range file.Replace {
+		if replace.Old.Path != mod.Path {
+			continue
+		}
+		// A replacement of a specific version takes precedence over a replacement of every version
+		if replace.Old.Version == mod.Version {
+			effective = replace.New
+			break
+		}
+		if replace.Old.Version == "" {
+			effective = replace.New
+		}
+	}
+
+	if effective.Version == "" || modfile.IsDirectoryPath(effective.Path) {
+		return types.Package{}, false
+	}
+
+	return types.Package{Name: effective.Path, Version: effective.Version, Ecosystem: types.EcosystemGo}, true
+}
+
+// parseGoSum returns the highest version of every module whose content is checksummed in the go.sum,
+// which is the version minimal version selection picks. Lines only covering a go.mod file are skipped.
+func parseGoSum(goSum []byte) []module.Version {
+	selected := make(map[string]string)
+	var order []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(goSum))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+
+		path, version := fields[0], fields[1]
+		current, ok := selected[path]
+		if !ok {
+			order = append(order, path)
+		}
+		if !ok || semver.Compare(version, current) > 0 {
+			selected[path] = version
+		}
+	}
+
+	var modules []module.Version
+	for _, path := range order {
+		modules = append(modules, module.Version{Path: path, Version: selected[path]})
+	}
+
+	return modules
+}
@@ -0,0 +1,342 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	types "khazande/internal/types"
+)
+
+// parseNpm accepts a package-lock.json (lockfile version 1, 2 or 3) or, as a fallback,
+// a package.json whose dependencies are pinned to exact versions
+func parseNpm(content []byte) ([]types.Package, error) {
+	var lock struct {
+		LockfileVersion int                       `json:"lockfileVersion"`
+		Packages        map[string]npmLockPackage `json:"packages"`
+		Dependencies    map[string]npmLockPackage `json:"dependencies"`
+	}
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return nil, fmt.Errorf("invalid package-lock.json or package.json: %v", err)
+	}
+
+	var packages []types.Package
+	seen := make(map[string]bool)
+	add := func(name, version string) {
+		key := name + "@" + version
+		if name == "" || version == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		packages = append(packages, types.Package{Name: name, Version: version})
+	}
+
+	switch {
+	case lock.LockfileVersion >= 2 && lock.Packages != nil:
+		for path, pkg := range lock.Packages {
+			// The empty path is the root project itself
+			if path == "" || pkg.Link {
+				continue
+			}
+			name := pkg.Name
+			if name == "" {
+				name = path[strings.LastIndex(path, "node_modules/")+len("node_modules/"):]
+			}
+			add(name, pkg.Version)
+		}
+	case lock.LockfileVersion == 1:
+		var walk func(dependencies map[string]npmLockPackage)
+		walk = func(dependencies map[string]npmLockPackage) {
+			for name, pkg := range dependencies {
+				add(name, pkg.Version)
+				walk(pkg.Dependencies)
+			}
+		}
+		walk(lock.Dependencies)
+	default:
+		var manifest struct {
+			Dependencies    map[string]string `json:"dependencies"`
+			DevDependencies map[string]string `json:"devDependencies"`
+		}
+		if err := json.Unmarshal(content, &manifest); err != nil {
+			return nil, fmt.Errorf("invalid package.json: %v", err)
+		}
+		for _, dependencies := range []map[string]string{manifest.Dependencies, manifest.DevDependencies} {
+			for name, version := range dependencies {
+				// Only exact versions can be checked, ranges like ^1.2.0 || 2.x are skipped
+				version = strings.TrimLeft(strings.TrimSpace(version), "=v")
+				if exactVersion.MatchString(version) {
+					add(name, version)
+				}
+			}
+		}
+	}
+
+	return packages, nil
+}
+
+type npmLockPackage struct {
+	Name         string                    `json:"name"`
+	Version      string                    `json:"version"`
+	Link         bool                      `json:"link"`
+	Dependencies map[string]npmLockPackage `json:"dependencies"`
+}
+
+var exactVersion = regexp.MustCompile(`^[0-9]+(\.[0-9]+)*([-+][0-9A-Za-z.-]+)?$`)
+
+// parseRequirements reads pinned requirements (name==version) of a pip requirements.txt
+func parseRequirements(content []byte) []types.Package {
+	var packages []types.Package
+	re := regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)(\[[^\]]*\])?\s*===?\s*([^\s;#]+)`)
+	separators := regexp.MustCompile(`[-_.]+`)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue
+		}
+		if match := re.FindStringSubmatch(line); match != nil {
+			// PyPI names are case insensitive and treat -, _ and . the same
+			name := strings.ToLower(separators.ReplaceAllString(match[1], "-"))
+			packages = append(packages, types.Package{Name: name, Version: match[3]})
+		}
+	}
+
+	return packages
+}
+
+// parsePom reads the dependencies of a pom.xml, resolving ${...} placeholders from the
+// <properties> section. Maven packages are named groupId:artifactId.
+func parsePom(content []byte) ([]types.Package, error) {
+	var project struct {
+		Version string `xml:"version"`
+		Parent  struct {
+			Version string `xml:"version"`
+		} `xml:"parent"`
+		Properties struct {
+			Entries []struct {
+				XMLName xml.Name
+				Value   string `xml:",chardata"`
+			} `xml:",any"`
+		} `xml:"properties"`
+		Dependencies []pomDependency `xml:"dependencies>dependency"`
+		Management   []pomDependency `xml:"dependencyManagement>dependencies>dependency"`
+	}
+	if err := xml.Unmarshal(content, &project); err != nil {
+		return nil, fmt.Errorf("invalid pom.xml: %v", err)
+	}
+
+	properties := map[string]string{
+		"project.version":        project.Version,
+		"project.parent.version": project.Parent.Version,
+	}
+	for _, entry := range project.Properties.Entries {
+		properties[entry.XMLName.Local] = strings.TrimSpace(entry.Value)
+	}
+	placeholder := regexp.MustCompile(`\$\{([^}]+)\}`)
+	resolve := func(value string) string {
+		return placeholder.ReplaceAllStringFunc(value, func(match string) string {
+			return properties[match[2:len(match)-1]]
+		})
+	}
+
+	// Versions declared in dependencyManagement apply to dependencies without one
+	managed := make(map[string]string)
+	for _, dependency := range project.Management {
+		managed[dependency.GroupID+":"+dependency.ArtifactID] = resolve(dependency.Version)
+	}
+
+	var packages []types.Package
+	for _, dependency := range project.Dependencies {
+		name := resolve(dependency.GroupID) + ":" + resolve(dependency.ArtifactID)
+		version := resolve(strings.TrimSpace(dependency.Version))
+		if version == "" {
+			version = managed[name]
+		}
+		// Version ranges such as [1.0,2.0) don't pin a single version
+		if version == "" || strings.ContainsAny(version, "[](),$") {
+			continue
+		}
+		packages = append(packages, types.Package{Name: name, Version: version})
+	}
+
+	return packages, nil
+}
+
+type pomDependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+}
+
+// parseGemfileLock reads the resolved gems listed under the specs of a Gemfile.lock
+func parseGemfileLock(content []byte) []types.Package {
+	var packages []types.Package
+	re := regexp.MustCompile(`^ {4}([^ ]+) \(([^)]+)\)$`)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		match := re.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		// Platform specific gems carry the platform after the version, e.g. 1.16.0-x86_64-linux
+		version := strings.SplitN(match[2], "-", 2)[0]
+		packages = append(packages, types.Package{Name: match[1], Version: version})
+	}
+
+	return packages
+}
+
+// parseNuGet reads a packages.config or the PackageReference items of a .csproj file
+func parseNuGet(content []byte) ([]types.Package, error) {
+	var packages []types.Package
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+
+	var current *types.Package
+	inVersion := false
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid NuGet project file: %v", err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "package", "PackageReference":
+				current = &types.Package{}
+				for _, attribute := range element.Attr {
+					switch attribute.Name.Local {
+					case "id", "Include", "Update":
+						current.Name = attribute.Value
+					case "version", "Version":
+						current.Version = attribute.Value
+					}
+				}
+			case "Version":
+				inVersion = current != nil
+			}
+		case xml.CharData:
+			if inVersion {
+				current.Version += strings.TrimSpace(string(element))
+			}
+		case xml.EndElement:
+			switch element.Name.Local {
+			case "package", "PackageReference":
+				if current != nil && current.Name != "" && current.Version != "" {
+					packages = append(packages, *current)
+				}
+				current = nil
+			case "Version":
+				inVersion = false
+			}
+		}
+	}
+
+	return packages, nil
+}
+
+// parseCargoLock reads the [[package]] tables of a Cargo.lock
+func parseCargoLock(content []byte) []types.Package {
+	var packages []types.Package
+	var current *types.Package
+
+	flush := func() {
+		if current != nil && current.Name != "" && current.Version != "" {
+			packages = append(packages, *current)
+		}
+		current = nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "[[package]]":
+			flush()
+			current = &types.Package{}
+		case strings.HasPrefix(line, "["):
+			flush()
+		case current != nil:
+			key, value, found := strings.Cut(line, "=")
+			if !found {
+				continue
+			}
+			value = strings.Trim(strings.TrimSpace(value), `"`)
+			switch strings.TrimSpace(key) {
+			case "name":
+				current.Name = value
+			case "version":
+				current.Version = value
+			}
+		}
+	}
+	flush()
+
+	return packages
+}
+
+// parseComposerLock reads the packages and dev packages of a composer.lock
+func parseComposerLock(content []byte) ([]types.Package, error) {
+	var lock struct {
+		Packages    []composerPackage `json:"packages"`
+		PackagesDev []composerPackage `json:"packages-dev"`
+	}
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return nil, fmt.Errorf("invalid composer.lock: %v", err)
+	}
+
+	var packages []types.Package
+	for _, pkg := range append(lock.Packages, lock.PackagesDev...) {
+		// Composer tags are often prefixed with v, e.g. v5.4.0
+		packages = append(packages, types.Package{Name: pkg.Name, Version: strings.TrimPrefix(pkg.Version, "v")})
+	}
+
+	return packages, nil
+}
+
+type composerPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// parsePubspecLock reads the packages section of a pubspec.lock
+func parsePubspecLock(content []byte) []types.Package {
+	var packages []types.Package
+	var name string
+	inPackages := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		indentation := len(line) - len(strings.TrimLeft(line, " "))
+
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+		case indentation == 0:
+			inPackages = trimmed == "packages:"
+		case !inPackages:
+		case indentation == 2 && strings.HasSuffix(trimmed, ":"):
+			name = strings.Trim(strings.TrimSuffix(trimmed, ":"), `"`)
+		case indentation == 4 && strings.HasPrefix(trimmed, "version:"):
+			version := strings.Trim(strings.TrimSpace(strings.TrimPrefix(trimmed, "version:")), `"'`)
+			if name != "" && version != "" {
+				packages = append(packages, types.Package{Name: name, Version: version})
+			}
+		}
+	}
+
+	return packages
+}
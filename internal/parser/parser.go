@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	types "khazande/internal/types"
+)
+
+var ecosystemAliases = map[string]types.Ecosystem{
+	"go":        types.EcosystemGo,
+	"golang":    types.EcosystemGo,
+	"npm":       types.EcosystemNpm,
+	"pip":       types.EcosystemPip,
+	"pypi":      types.EcosystemPip,
+	"maven":     types.EcosystemMaven,
+	"rubygems":  types.EcosystemRubyGems,
+	"gem":       types.EcosystemRubyGems,
+	"nuget":     types.EcosystemNuGet,
+	"rust":      types.EcosystemRust,
+	"cargo":     types.EcosystemRust,
+	"crates.io": types.EcosystemRust,
+	"composer":  types.EcosystemComposer,
+	"packagist": types.EcosystemComposer,
+	"pub":       types.EcosystemPub,
+}
+
+// ParseEcosystem maps a user supplied ecosystem name (case insensitive) to the ecosystem
+// understood by the GitHub Advisory Database
+func ParseEcosystem(name string) (types.Ecosystem, error) {
+	ecosystem, ok := ecosystemAliases[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return "", fmt.Errorf("unsupported ecosystem %q", name)
+	}
+
+	return ecosystem, nil
+}
+
+// Parse extracts the packages and their versions out of a manifest or lock file of the ecosystem
+func Parse(ecosystem types.Ecosystem, content []byte) ([]types.Package, error) {
+	var packages []types.Package
+	var err error
+
+	switch ecosystem {
+	case types.EcosystemGo:
+		packages, err = ParseGoMod(content, nil)
+	case types.EcosystemNpm:
+		packages, err = parseNpm(content)
+	case types.EcosystemPip:
+		packages = parseRequirements(content)
+	case types.EcosystemMaven:
+		packages, err = parsePom(content)
+	case types.EcosystemRubyGems:
+		packages = parseGemfileLock(content)
+	case types.EcosystemNuGet:
+		packages, err = parseNuGet(content)
+	case types.EcosystemRust:
+		packages = parseCargoLock(content)
+	case types.EcosystemComposer:
+		packages, err = parseComposerLock(content)
+	case types.EcosystemPub:
+		packages = parsePubspecLock(content)
+	default:
+		return nil, fmt.Errorf("unsupported ecosystem %q", ecosystem)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range packages {
+		packages[i].Ecosystem = ecosystem
+	}
+
+	return packages, nil
+}
@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+
+	purlModule "khazande/internal/purl"
+	types "khazande/internal/types"
+)
+
+type cycloneDXComponent struct {
+	BomRef     string               `json:"bom-ref"`
+	Name       string               `json:"name"`
+	Version    string               `json:"version"`
+	Purl       string               `json:"purl"`
+	Components []cycloneDXComponent `json:"components"`
+}
+
+// ParseCycloneDX maps the components of a CycloneDX JSON SBOM, including nested ones, to packages
+// through their purls. Components without a purl of a supported ecosystem are skipped.
+func ParseCycloneDX(content []byte) ([]types.Package, error) {
+	var bom struct {
+		BomFormat  string               `json:"bomFormat"`
+		Components []cycloneDXComponent `json:"components"`
+	}
+	if err := json.Unmarshal(content, &bom); err != nil {
+		return nil, fmt.Errorf("invalid CycloneDX SBOM: %v", err)
+	}
+	if bom.BomFormat != "CycloneDX" {
+		return nil, fmt.Errorf("invalid CycloneDX SBOM: unexpected bomFormat %q", bom.BomFormat)
+	}
+
+	var packages []types.Package
+	var walk func(components []cycloneDXComponent)
+	walk = func(components []cycloneDXComponent) {
+		for _, component := range components {
+			if pkg, ok := packageFromPurl(component.Purl, component.Version); ok {
+				pkg.BomRef = component.BomRef
+				packages = append(packages, pkg)
+			}
+			walk(component.Components)
+		}
+	}
+	walk(bom.Components)
+
+	return packages, nil
+}
+
+// ParseSPDX maps the packages of an SPDX JSON SBOM to packages through their purl external
+// references. The SPDXID of the package is kept as its reference.
+func ParseSPDX(content []byte) ([]types.Package, error) {
+	var document struct {
+		SPDXVersion string `json:"spdxVersion"`
+		Packages    []struct {
+			SPDXID       string `json:"SPDXID"`
+			VersionInfo  string `json:"versionInfo"`
+			ExternalRefs []struct {
+				ReferenceCategory string `json:"referenceCategory"`
+				ReferenceType     string `json:"referenceType"`
+				ReferenceLocator  string `json:"referenceLocator"`
+			} `json:"externalRefs"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("invalid SPDX SBOM: %v", err)
+	}
+	if document.SPDXVersion == "" {
+		return nil, fmt.Errorf("invalid SPDX SBOM: missing spdxVersion")
+	}
+
+	var packages []types.Package
+	for _, spdxPackage := range document.Packages {
+		for _, reference := range spdxPackage.ExternalRefs {
+			if reference.ReferenceType != "purl" {
+				continue
+			}
+			if pkg, ok := packageFromPurl(reference.ReferenceLocator, spdxPackage.VersionInfo); ok {
+				pkg.BomRef = spdxPackage.SPDXID
+				packages = append(packages, pkg)
+				break
+			}
+		}
+	}
+
+	return packages, nil
+}
+
+// packageFromPurl falls back to the version declared next to the purl when the purl has none
+func packageFromPurl(purl string, version string) (types.Package, bool) {
+	if purl == "" {
+		return types.Package{}, false
+	}
+
+	parsed, err := purlModule.Parse(purl)
+	if err != nil {
+		return types.Package{}, false
+	}
+
+	pkg, ok := parsed.Package()
+	if !ok {
+		return types.Package{}, false
+	}
+	if pkg.Version == "" {
+		pkg.Version = version
+	}
+
+	return pkg, pkg.Version != ""
+}
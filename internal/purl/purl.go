@@ -0,0 +1,218 @@
+package purl
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	distroModule "khazande/internal/distro"
+	types "khazande/internal/types"
+)
+
+// PackageURL is a parsed package URL as described by https://github.com/package-url/purl-spec,
+// e.g. pkg:golang/github.com/gin-gonic/gin@v1.9.1
+type PackageURL struct {
+	Type       string
+	Namespace  string
+	Name       string
+	Version    string
+	Qualifiers map[string]string
+	Subpath    string
+}
+
+// purl types of the ecosystems the advisory sources know about
+var ecosystems = map[string]types.Ecosystem{
+	"golang":   types.EcosystemGo,
+	"npm":      types.EcosystemNpm,
+	"pypi":     types.EcosystemPip,
+	"maven":    types.EcosystemMaven,
+	"gem":      types.EcosystemRubyGems,
+	"nuget":    types.EcosystemNuGet,
+	"cargo":    types.EcosystemRust,
+	"composer": types.EcosystemComposer,
+	"pub":      types.EcosystemPub,
+	"apk":      types.EcosystemAlpine,
+	"deb":      types.EcosystemDebian,
+}
+
+// Parse splits a purl into its components, percent-decoding them
+func Parse(purl string) (PackageURL, error) {
+	var p PackageURL
+
+	remainder, found := strings.CutPrefix(strings.TrimSpace(purl), "pkg:")
+	if !found {
+		return p, fmt.Errorf("invalid purl %q: missing pkg: scheme", purl)
+	}
+
+	if index := strings.LastIndex(remainder, "#"); index != -1 {
+		p.Subpath = strings.Trim(remainder[index+1:], "/")
+		remainder = remainder[:index]
+	}
+
+	if index := strings.LastIndex(remainder, "?"); index != -1 {
+		qualifiers, err := url.ParseQuery(remainder[index+1:])
+		if err != nil {
+			return p, fmt.Errorf("invalid purl %q: %v", purl, err)
+		}
+		p.Qualifiers = make(map[string]string)
+		for key, values := range qualifiers {
+			p.Qualifiers[strings.ToLower(key)] = values[0]
+		}
+		remainder = remainder[:index]
+	}
+
+	// The version follows the last @ of the name, npm scopes like @angular are part of the namespace
+	if index := strings.LastIndex(remainder, "@"); index > strings.LastIndex(remainder, "/") {
+		version, err := url.PathUnescape(remainder[index+1:])
+		if err != nil {
+			return p, fmt.Errorf("invalid purl %q: %v", purl, err)
+		}
+		p.Version = version
+		remainder = remainder[:index]
+	}
+
+	segments := strings.Split(strings.Trim(remainder, "/"), "/")
+	if len(segments) < 2 {
+		return p, fmt.Errorf("invalid purl %q: missing type or name", purl)
+	}
+
+	p.Type = strings.ToLower(segments[0])
+	for i, segment := range segments[1:] {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil {
+			return p, fmt.Errorf("invalid purl %q: %v", purl, err)
+		}
+		segments[i+1] = unescaped
+	}
+	p.Name = segments[len(segments)-1]
+	p.Namespace = strings.Join(segments[1:len(segments)-1], "/")
+
+	return p, nil
+}
+
+func (p PackageURL) String() string {
+	var builder strings.Builder
+	builder.WriteString("pkg:" + p.Type + "/")
+
+	if p.Namespace != "" {
+		for _, segment := range strings.Split(p.Namespace, "/") {
+			builder.WriteString(escape(segment) + "/")
+		}
+	}
+	builder.WriteString(escape(p.Name))
+
+	if p.Version != "" {
+		builder.WriteString("@" + url.PathEscape(p.Version))
+	}
+
+	if len(p.Qualifiers) != 0 {
+		keys := make([]string, 0, len(p.Qualifiers))
+		for key := range p.Qualifiers {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for i, key := range keys {
+			separator := "&"
+			if i == 0 {
+				separator = "?"
+			}
+			builder.WriteString(separator + key + "=" + url.QueryEscape(p.Qualifiers[key]))
+		}
+	}
+
+	if p.Subpath != "" {
+		builder.WriteString("#" + p.Subpath)
+	}
+
+	return builder.String()
+}
+
+// Package maps the purl to the ecosystem, name and version used by the advisory sources.
+// It reports false for purl types no source covers.
+func (p PackageURL) Package() (types.Package, bool) {
+	ecosystem, ok := ecosystems[p.Type]
+	if !ok {
+		return types.Package{}, false
+	}
+
+	name := p.Name
+	switch {
+	case p.Namespace == "":
+	case ecosystem == types.EcosystemAlpine || ecosystem == types.EcosystemDebian:
+		// The namespace of OS packages is the distribution, not part of the name
+	case ecosystem == types.EcosystemMaven:
+		// Maven packages are named groupId:artifactId
+		name = p.Namespace + ":" + p.Name
+	default:
+		name = p.Namespace + "/" + p.Name
+	}
+
+	if ecosystem == types.EcosystemPip {
+		name = strings.ToLower(strings.ReplaceAll(name, "_", "-"))
+	}
+
+	pkg := types.Package{Name: name, Version: p.Version, Ecosystem: ecosystem}
+	if ecosystem == types.EcosystemAlpine || ecosystem == types.EcosystemDebian {
+		p.sourcePackage(&pkg)
+	}
+
+	return pkg, true
+}
+
+// sourcePackage moves an OS package to the source package the distributions advise about, named
+// by the upstream qualifier (e.g. "openssl" or "glibc@2.36-9"), and to the release of the distro
+// qualifier (e.g. "alpine-3.18.4" or "debian-12")
+func (p PackageURL) sourcePackage(pkg *types.Package) {
+	if upstream := p.Qualifiers["upstream"]; upstream != "" {
+		name, version, ok := strings.Cut(upstream, "@")
+		pkg.Name = name
+		if ok {
+			pkg.Version = version
+		}
+	}
+
+	distro := p.Qualifiers["distro"]
+	switch pkg.Ecosystem {
+	case types.EcosystemAlpine:
+		pkg.Release = distroModule.AlpineRelease(strings.TrimPrefix(distro, "alpine-"))
+	case types.EcosystemDebian:
+		pkg.Release = strings.TrimPrefix(distro, "debian-")
+	}
+}
+
+// FromPackage builds the purl of a package, the inverse of Package
+func FromPackage(pkg types.Package) PackageURL {
+	p := PackageURL{Name: pkg.Name, Version: pkg.Version}
+	for purlType, ecosystem := range ecosystems {
+		if ecosystem == pkg.Ecosystem {
+			p.Type = purlType
+		}
+	}
+	if p.Type == "" {
+		p.Type = "generic"
+	}
+
+	switch pkg.Ecosystem {
+	case types.EcosystemAlpine:
+		p.Namespace = "alpine"
+		return p
+	case types.EcosystemDebian:
+		p.Namespace = "debian"
+		return p
+	}
+
+	separator := "/"
+	if pkg.Ecosystem == types.EcosystemMaven {
+		separator = ":"
+	}
+	if index := strings.LastIndex(pkg.Name, separator); index != -1 {
+		p.Namespace, p.Name = pkg.Name[:index], pkg.Name[index+1:]
+	}
+
+	return p
+}
+
+func escape(segment string) string {
+	return strings.ReplaceAll(url.PathEscape(segment), "@", "%40")
+}
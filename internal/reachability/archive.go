@@ -0,0 +1,113 @@
+package reachability
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+const (
+	// maxFileSize caps a file of the source tree once decompressed
+	maxFileSize = 16 * 1024 * 1024
+	// maxTotalSize caps the files kept from the source tree once decompressed
+	maxTotalSize = 512 * 1024 * 1024
+)
+
+// ReadArchive extracts the Go files, go.mod and go.sum files of a zip, tar or tar.gz archive of a
+// source tree, keyed by their slash separated path in the archive. Archives expanding beyond
+// maxFileSize for a file or maxTotalSize in all are rejected.
+func ReadArchive(content []byte) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	remaining := int64(maxTotalSize)
+
+	read := func(name string, reader io.Reader) ([]byte, error) {
+		limit := min(maxFileSize, remaining)
+		content, err := io.ReadAll(io.LimitReader(reader, limit+1))
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case len(content) > maxFileSize:
+			return nil, fmt.Errorf("%s is larger than %d MiB decompressed", name, maxFileSize>>20)
+		case int64(len(content)) > remaining:
+			return nil, fmt.Errorf("the source tree is larger than %d MiB decompressed", maxTotalSize>>20)
+		}
+		remaining -= int64(len(content))
+
+		return content, nil
+	}
+
+	keep := func(name string) (string, bool) {
+		name = path.Clean(strings.TrimPrefix(name, "./"))
+		if strings.HasPrefix(name, "../") || strings.HasPrefix(name, "/") {
+			return "", false
+		}
+		base := path.Base(name)
+		return name, strings.HasSuffix(base, ".go") || base == "go.mod" || base == "go.sum"
+	}
+
+	if bytes.HasPrefix(content, []byte("PK\x03\x04")) {
+		archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read zip archive: %v", err)
+		}
+
+		for _, file := range archive.File {
+			name, ok := keep(file.Name)
+			if !ok || file.FileInfo().IsDir() {
+				continue
+			}
+
+			reader, err := file.Open()
+			if err != nil {
+				return nil, err
+			}
+			files[name], err = read(name, reader)
+			reader.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return files, nil
+	}
+
+	var reader io.Reader = bytes.NewReader(content)
+	if bytes.HasPrefix(content, []byte{0x1f, 0x8b}) {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gzip archive: %v", err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	archive := tar.NewReader(reader)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar archive: %v", err)
+		}
+
+		name, ok := keep(header.Name)
+		if !ok || header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		files[name], err = read(name, archive)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,551 @@
+package reachability
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"khazande/internal/types"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// Symbol is a function or method of a package. Methods are named Type.Method, a method called on
+// a value whose type isn't known is named *.Method and stands for the methods of every type.
+type Symbol struct {
+	Package string
+	Name    string
+}
+
+func (s Symbol) String() string {
+	return s.Package + "." + s.Name
+}
+
+func (s Symbol) wildcard() bool {
+	return strings.HasPrefix(s.Name, "*.")
+}
+
+// Graph is a call graph of a Go module built from the syntax of its files alone, without type
+// checking. It over-approximates: a method call links to the methods of that name of every
+// package the file imports. Dependencies are only followed into when their source is vendored,
+// otherwise the graph ends at the calls the module makes into them.
+type Graph struct {
+	Module string
+	GoMod  []byte
+	GoSum  []byte
+
+	edges   map[Symbol]map[Symbol]bool
+	entries []Symbol
+	methods map[string]map[string][]Symbol
+	// analyzed are the packages whose source is in the graph, the module's and the vendored ones
+	analyzed map[string]bool
+	// requires maps the modules the go.mod requires to whether they are indirect, pruned is set
+	// when the go.mod lists every module the build needs (go 1.17 and later)
+	requires map[string]bool
+	pruned   bool
+
+	// Breadth-first search from the entries, filled on first use
+	parents map[Symbol]Symbol
+	depths  map[Symbol]int
+}
+
+type sourceFile struct {
+	importPath string
+	moduleCode bool
+	file       *ast.File
+}
+
+// Analyze builds the call graph of the module whose go.mod is the closest to the root of the files.
+// The entries are main and the init functions when the module has a main package, every exported
+// function and method otherwise.
+func Analyze(files map[string][]byte) (*Graph, error) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	root := ""
+	var nested []string
+	for _, name := range names {
+		if path.Base(name) != "go.mod" {
+			continue
+		}
+		dir := path.Dir(name)
+		if root == "" || strings.Count(dir, "/") < strings.Count(root, "/") || dir == "." {
+			root = dir
+		}
+	}
+	if root == "" {
+		return nil, fmt.Errorf("the source tree has no go.mod")
+	}
+	for _, name := range names {
+		if dir, ok := relative(root, path.Dir(name)); ok && path.Base(name) == "go.mod" && dir != "." && !strings.HasPrefix(dir, "vendor/") {
+			nested = append(nested, dir)
+		}
+	}
+
+	graph := &Graph{
+		GoMod:    files[path.Join(root, "go.mod")],
+		GoSum:    files[path.Join(root, "go.sum")],
+		analyzed: make(map[string]bool),
+		edges:    make(map[Symbol]map[Symbol]bool),
+		methods:  make(map[string]map[string][]Symbol),
+	}
+	graph.Module = modfile.ModulePath(graph.GoMod)
+	if graph.Module == "" {
+		return nil, fmt.Errorf("the go.mod of the source tree has no module path")
+	}
+	if goMod, err := modfile.ParseLax("go.mod", graph.GoMod, nil); err == nil {
+		graph.requires = make(map[string]bool)
+		for _, require := range goMod.Require {
+			graph.requires[require.Mod.Path] = require.Indirect
+		}
+		graph.pruned = goMod.Go != nil && semver.Compare("v"+goMod.Go.Version, "v1.17") >= 0
+	}
+
+	fset := token.NewFileSet()
+	var sources []sourceFile
+	packageNames := make(map[string]string)
+	functions := make(map[string]map[string]bool)
+	hasMain := false
+
+	for _, name := range names {
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		dir, ok := relative(root, path.Dir(name))
+		if !ok || skipDir(dir) || inside(dir, nested) {
+			continue
+		}
+
+		// Files that don't parse are left out of the graph
+		file, err := parser.ParseFile(fset, name, files[name], parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+
+		source := sourceFile{importPath: graph.Module, moduleCode: true, file: file}
+		switch {
+		case dir == "vendor" || strings.HasPrefix(dir, "vendor/"):
+			source.importPath = strings.TrimPrefix(dir, "vendor/")
+			source.moduleCode = false
+		case dir != ".":
+			source.importPath = graph.Module + "/" + dir
+		}
+		sources = append(sources, source)
+		graph.analyzed[source.importPath] = true
+
+		packageNames[source.importPath] = file.Name.Name
+		if source.moduleCode && file.Name.Name == "main" {
+			hasMain = true
+		}
+		if functions[source.importPath] == nil {
+			functions[source.importPath] = make(map[string]bool)
+		}
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok {
+				symbol := symbolOf(source.importPath, fn)
+				if fn.Recv == nil {
+					functions[source.importPath][fn.Name.Name] = true
+				} else {
+					graph.addMethod(symbol, fn.Name.Name)
+				}
+			}
+		}
+	}
+
+	entries := make(map[Symbol]bool)
+	for _, source := range sources {
+		own := source.importPath
+		aliases := make(map[string]string)
+		imports := []string{own}
+		for _, spec := range source.file.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				continue
+			}
+			imports = append(imports, importPath)
+
+			alias, ok := packageNames[importPath]
+			if !ok {
+				alias = guessPackageName(importPath)
+			}
+			if spec.Name != nil {
+				alias = spec.Name.Name
+			}
+			if alias != "_" && alias != "." {
+				aliases[alias] = importPath
+			}
+		}
+
+		isEntry := func(fn *ast.FuncDecl) bool {
+			if !source.moduleCode {
+				return false
+			}
+			if fn.Name.Name == "init" && fn.Recv == nil {
+				return source.file.Name.Name == "main" || !hasMain
+			}
+			if hasMain {
+				return source.file.Name.Name == "main" && fn.Name.Name == "main" && fn.Recv == nil
+			}
+			return ast.IsExported(fn.Name.Name)
+		}
+
+		initializer := Symbol{Package: own, Name: "init"}
+		for _, decl := range source.file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				symbol := symbolOf(own, decl)
+				if decl.Body != nil {
+					graph.collect(symbol, decl.Body, aliases, imports, functions[own])
+				}
+				if isEntry(decl) {
+					entries[symbol] = true
+				}
+			case *ast.GenDecl:
+				// Package level variables are initialized along with the init functions
+				for _, spec := range decl.Specs {
+					if value, ok := spec.(*ast.ValueSpec); ok {
+						for _, expr := range value.Values {
+							graph.collect(initializer, expr, aliases, imports, functions[own])
+						}
+					}
+				}
+				if source.moduleCode && (source.file.Name.Name == "main" || !hasMain) {
+					entries[initializer] = true
+				}
+			}
+		}
+	}
+
+	// A method called on a value of unknown type may be any method of that name
+	for importPath, methods := range graph.methods {
+		for name, symbols := range methods {
+			for _, symbol := range symbols {
+				graph.addEdge(Symbol{Package: importPath, Name: "*." + name}, symbol)
+			}
+		}
+	}
+
+	for entry := range entries {
+		graph.entries = append(graph.entries, entry)
+	}
+	sort.Slice(graph.entries, func(i, j int) bool {
+		return graph.entries[i].String() < graph.entries[j].String()
+	})
+
+	return graph, nil
+}
+
+// collect adds an edge from the function to everything its body calls or references
+func (graph *Graph) collect(from Symbol, node ast.Node, aliases map[string]string, imports []string, functions map[string]bool) {
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			if ident, ok := n.X.(*ast.Ident); ok {
+				if importPath, ok := aliases[ident.Name]; ok {
+					graph.addEdge(from, Symbol{Package: importPath, Name: n.Sel.Name})
+					return false
+				}
+			}
+
+			for _, importPath := range imports {
+				graph.addEdge(from, Symbol{Package: importPath, Name: "*." + n.Sel.Name})
+			}
+			ast.Inspect(n.X, visit)
+			return false
+		case *ast.Ident:
+			if functions[n.Name] {
+				graph.addEdge(from, Symbol{Package: from.Package, Name: n.Name})
+			}
+		}
+		return true
+	}
+
+	ast.Inspect(node, visit)
+}
+
+func (graph *Graph) addEdge(from Symbol, to Symbol) {
+	if graph.edges[from] == nil {
+		graph.edges[from] = make(map[Symbol]bool)
+	}
+	graph.edges[from][to] = true
+}
+
+func (graph *Graph) addMethod(symbol Symbol, name string) {
+	if graph.methods[symbol.Package] == nil {
+		graph.methods[symbol.Package] = make(map[string][]Symbol)
+	}
+	graph.methods[symbol.Package][name] = append(graph.methods[symbol.Package][name], symbol)
+}
+
+func (graph *Graph) search() {
+	if graph.parents != nil {
+		return
+	}
+
+	graph.parents = make(map[Symbol]Symbol)
+	graph.depths = make(map[Symbol]int)
+	queue := append([]Symbol(nil), graph.entries...)
+	for _, entry := range graph.entries {
+		graph.depths[entry] = 0
+	}
+
+	for len(queue) != 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		callees := make([]Symbol, 0, len(graph.edges[current]))
+		for callee := range graph.edges[current] {
+			callees = append(callees, callee)
+		}
+		sort.Slice(callees, func(i, j int) bool {
+			return callees[i].String() < callees[j].String()
+		})
+
+		for _, callee := range callees {
+			if _, seen := graph.depths[callee]; seen {
+				continue
+			}
+			graph.depths[callee] = graph.depths[current] + 1
+			graph.parents[callee] = current
+			queue = append(queue, callee)
+		}
+	}
+}
+
+// Check marks the Go vulnerability as reachable, with the shortest call stack from an entry to a
+// vulnerable symbol. A vulnerability that isn't reached is only unreachable when no dependency
+// outside the graph is called that could lead to it, otherwise it is not analyzed.
+//
+// Without the vulnerable imports of the Go vulndb, as for GitHub advisories, the check is coarser:
+// any use of a package of the module makes it module reachable. The standard library and toolchain
+// are not analyzed then. Findings of other ecosystems are left as is.
+func (graph *Graph) Check(vulnerability *types.Vulnerability) {
+	if vulnerability.Ecosystem != "" && vulnerability.Ecosystem != types.EcosystemGo {
+		return
+	}
+	graph.search()
+
+	standard := vulnerability.Name == types.GoStdlib || vulnerability.Name == types.GoToolchain
+	moduleLevel := len(vulnerability.Imports) == 0
+
+	var best Symbol
+	var target Symbol
+	found := false
+	consider := func(reached Symbol, vulnerable Symbol) {
+		depth, ok := graph.depths[reached]
+		if !ok {
+			return
+		}
+		if !found || depth < graph.depths[best] || (depth == graph.depths[best] && reached.String() < best.String()) {
+			best, target, found = reached, vulnerable, true
+		}
+	}
+
+	if len(vulnerability.Imports) != 0 {
+		for _, imported := range vulnerability.Imports {
+			if len(imported.Symbols) == 0 {
+				graph.considerPackage(func(importPath string) bool { return importPath == imported.Path }, consider)
+				continue
+			}
+
+			for _, name := range imported.Symbols {
+				symbol := Symbol{Package: imported.Path, Name: name}
+				consider(symbol, symbol)
+				if _, method, ok := strings.Cut(name, "."); ok {
+					consider(Symbol{Package: imported.Path, Name: "*." + method}, symbol)
+				}
+			}
+		}
+	} else {
+		if standard {
+			vulnerability.Reachability = types.NotAnalyzed
+			vulnerability.CallStack = nil
+			return
+		}
+		graph.considerPackage(func(importPath string) bool {
+			return importPath == vulnerability.Name || strings.HasPrefix(importPath, vulnerability.Name+"/")
+		}, consider)
+	}
+
+	if !found {
+		vulnerability.Reachability = types.Unreachable
+		if graph.callsUnanalyzed(vulnerability.Name, standard) {
+			vulnerability.Reachability = types.NotAnalyzed
+		}
+		vulnerability.CallStack = nil
+		return
+	}
+
+	var stack []string
+	for symbol, ok := best, true; ok; symbol, ok = graph.parents[symbol] {
+		switch {
+		case symbol == best:
+			stack = append(stack, target.String())
+		case !symbol.wildcard():
+			stack = append(stack, symbol.String())
+		}
+	}
+	for i, j := 0, len(stack)-1; i < j; i, j = i+1, j-1 {
+		stack[i], stack[j] = stack[j], stack[i]
+	}
+
+	vulnerability.Reachability = types.Reachable
+	if moduleLevel {
+		vulnerability.Reachability = types.ModuleReachable
+	}
+	vulnerability.CallStack = stack
+}
+
+// callsUnanalyzed reports whether the analyzed code calls into a package whose source isn't in the
+// graph and that may call the vulnerable module in turn. Any such package may call the standard
+// library. The standard library doesn't import other modules, and a dependency only leads to the
+// vulnerable module when it is that module or may require it, see mayRequire.
+func (graph *Graph) callsUnanalyzed(vulnerableModule string, standard bool) bool {
+	for symbol := range graph.depths {
+		if graph.analyzed[symbol.Package] {
+			continue
+		}
+		if standard {
+			return true
+		}
+		if isStandard(symbol.Package) {
+			continue
+		}
+
+		dependency := graph.moduleOf(symbol.Package)
+		if dependency == "" || dependency == vulnerableModule || graph.mayRequire(vulnerableModule) {
+			return true
+		}
+	}
+	return false
+}
+
+// moduleOf is the required module providing the package, the longest matching module path, or
+// empty when the go.mod requires none
+func (graph *Graph) moduleOf(importPath string) string {
+	provider := ""
+	for modulePath := range graph.requires {
+		if (importPath == modulePath || strings.HasPrefix(importPath, modulePath+"/")) && len(modulePath) > len(provider) {
+			provider = modulePath
+		}
+	}
+	return provider
+}
+
+// mayRequire reports whether dependencies of the module may require the vulnerable module. The go.mod
+// doesn't record which dependency requires what, a direct requirement of a pruned go.mod is taken to
+// be the module's own. Indirect requirements, modules only listed in go.sum and go.mod files older
+// than go 1.17 may come from any dependency.
+func (graph *Graph) mayRequire(vulnerableModule string) bool {
+	indirect, ok := graph.requires[vulnerableModule]
+	return !ok || indirect || !graph.pruned
+}
+
+// considerPackage considers every reached function of the matching packages
+func (graph *Graph) considerPackage(matches func(importPath string) bool, consider func(reached Symbol, vulnerable Symbol)) {
+	for symbol := range graph.depths {
+		if !symbol.wildcard() && matches(symbol.Package) {
+			consider(symbol, symbol)
+		}
+	}
+}
+
+// symbolOf names functions after themselves and methods after their receiver type
+func symbolOf(importPath string, fn *ast.FuncDecl) Symbol {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return Symbol{Package: importPath, Name: fn.Name.Name}
+	}
+
+	receiver := fn.Recv.List[0].Type
+	for {
+		switch expr := receiver.(type) {
+		case *ast.StarExpr:
+			receiver = expr.X
+			continue
+		case *ast.IndexExpr:
+			receiver = expr.X
+			continue
+		case *ast.IndexListExpr:
+			receiver = expr.X
+			continue
+		case *ast.ParenExpr:
+			receiver = expr.X
+			continue
+		case *ast.Ident:
+			return Symbol{Package: importPath, Name: expr.Name + "." + fn.Name.Name}
+		}
+		return Symbol{Package: importPath, Name: "*." + fn.Name.Name}
+	}
+}
+
+// guessPackageName guesses the name of a package whose source isn't available from its import
+// path: the last element, skipping major version suffixes, e.g. gopkg.in/yaml.v3 is yaml
+func guessPackageName(importPath string) string {
+	elements := strings.Split(importPath, "/")
+	name := elements[len(elements)-1]
+	if len(elements) > 1 && isMajorVersion(name) {
+		name = elements[len(elements)-2]
+	}
+	if index := strings.Index(name, ".v"); index > 0 && isMajorVersion(name[index+1:]) {
+		name = name[:index]
+	}
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "go-"), "-go")
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// isStandard tells the packages of the standard library apart, their first path element has no dot
+func isStandard(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
+
+func isMajorVersion(element string) bool {
+	if len(element) < 2 || element[0] != 'v' {
+		return false
+	}
+	_, err := strconv.Atoi(element[1:])
+	return err == nil
+}
+
+// relative returns the directory relative to the root, reporting false for directories outside of it
+func relative(root string, dir string) (string, bool) {
+	if root == "." {
+		return dir, true
+	}
+	if dir == root {
+		return ".", true
+	}
+	if strings.HasPrefix(dir, root+"/") {
+		return strings.TrimPrefix(dir, root+"/"), true
+	}
+	return "", false
+}
+
+// skipDir reports the directories the go command ignores
+func skipDir(dir string) bool {
+	for _, element := range strings.Split(dir, "/") {
+		if element == "testdata" || (element != "." && (strings.HasPrefix(element, ".") || strings.HasPrefix(element, "_"))) {
+			return true
+		}
+	}
+	return false
+}
+
+func inside(dir string, parents []string) bool {
+	for _, parent := range parents {
+		if dir == parent || strings.HasPrefix(dir, parent+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package reachability
+
+import (
+	"reflect"
+	"testing"
+
+	"khazande/internal/types"
+)
+
+const testGoMod = `module example.com/app
+
+go 1.21
+
+require (
+	github.com/google/uuid v1.3.0
+	github.com/example/vulnerable v1.0.0
+	golang.org/x/net v0.10.0 // indirect
+)
+`
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name          string
+		main          string
+		vulnerability types.Vulnerability
+		reachability  string
+		callStack     []string
+	}{
+		{
+			name: "unrelated dependency",
+			main: `package main
+
+import "github.com/google/uuid"
+
+func main() { uuid.New() }
+`,
+			vulnerability: types.Vulnerability{
+				Name:    "github.com/example/vulnerable",
+				Imports: []types.VulnerableImport{{Path: "github.com/example/vulnerable/parser", Symbols: []string{"Parse"}}},
+			},
+			reachability: types.Unreachable,
+		},
+		{
+			name: "indirect requirement behind a dependency",
+			main: `package main
+
+import "github.com/google/uuid"
+
+func main() { uuid.New() }
+`,
+			vulnerability: types.Vulnerability{
+				Name:    "golang.org/x/net",
+				Imports: []types.VulnerableImport{{Path: "golang.org/x/net/http2", Symbols: []string{"Server.ServeConn"}}},
+			},
+			reachability: types.NotAnalyzed,
+		},
+		{
+			name: "vulnerable module called elsewhere",
+			main: `package main
+
+import "github.com/example/vulnerable/parser"
+
+func main() { parser.Load() }
+`,
+			vulnerability: types.Vulnerability{
+				Name:    "github.com/example/vulnerable",
+				Imports: []types.VulnerableImport{{Path: "github.com/example/vulnerable/parser", Symbols: []string{"Parse"}}},
+			},
+			reachability: types.NotAnalyzed,
+		},
+		{
+			name: "vulnerable symbol called",
+			main: `package main
+
+import "github.com/example/vulnerable/parser"
+
+func main() { run() }
+
+func run() { parser.Parse() }
+`,
+			vulnerability: types.Vulnerability{
+				Name:    "github.com/example/vulnerable",
+				Imports: []types.VulnerableImport{{Path: "github.com/example/vulnerable/parser", Symbols: []string{"Parse"}}},
+			},
+			reachability: types.Reachable,
+			callStack:    []string{"example.com/app.main", "example.com/app.run", "github.com/example/vulnerable/parser.Parse"},
+		},
+		{
+			name: "module without vulnerable symbols",
+			main: `package main
+
+import "github.com/example/vulnerable/parser"
+
+func main() { parser.Load() }
+`,
+			vulnerability: types.Vulnerability{Name: "github.com/example/vulnerable"},
+			reachability:  types.ModuleReachable,
+			callStack:     []string{"example.com/app.main", "github.com/example/vulnerable/parser.Load"},
+		},
+		{
+			name: "standard library",
+			main: `package main
+
+import "fmt"
+
+func main() { fmt.Println() }
+`,
+			vulnerability: types.Vulnerability{
+				Name:    types.GoStdlib,
+				Imports: []types.VulnerableImport{{Path: "net/http", Symbols: []string{"Get"}}},
+			},
+			reachability: types.NotAnalyzed,
+		},
+	}
+
+	for _, test := range tests {
+		graph, err := Analyze(map[string][]byte{"go.mod": []byte(testGoMod), "main.go": []byte(test.main)})
+		if err != nil {
+			t.Fatalf("%s: Analyze failed: %v", test.name, err)
+		}
+
+		vulnerability := test.vulnerability
+		graph.Check(&vulnerability)
+		if vulnerability.Reachability != test.reachability {
+			t.Errorf("%s: reachability = %q, want %q", test.name, vulnerability.Reachability, test.reachability)
+		}
+		if !reflect.DeepEqual(vulnerability.CallStack, test.callStack) {
+			t.Errorf("%s: call stack = %v, want %v", test.name, vulnerability.CallStack, test.callStack)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	envsModule "khazande/pkg/envs"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
@@ -14,13 +15,13 @@ type Router struct {
 	Handler *handlersModule.Handler
 }
 
-func Initial(envs *envsModule.Envs, logger *zap.Logger) *Router {
+func Initial(envs *envsModule.Envs, logger *zap.Logger, redisClient *redis.Client) *Router {
 	return &Router{
 		Advisor: &advisorModule.Advisor{
 			Logger: logger,
 			Envs:   envs,
 		},
-		Handler: handlersModule.Initial(envs, logger),
+		Handler: handlersModule.Initial(envs, logger, redisClient),
 	}
 }
 
@@ -35,6 +36,8 @@ func (r *Router) SetupRouters(app *fiber.App) {
 	api := app.Group("/api")
 
 	api.Post("/fetch-vulnerabilities", r.Handler.VulnerabilityHandler())
+	api.Post("/scan-binary", r.Handler.BinaryHandler())
+	api.Post("/scan-image", r.Handler.ImageHandler())
 
 	// 404 - Not Found error handler
 	app.Use(func(c *fiber.Ctx) error {
@@ -1,28 +1,191 @@
 package types
 
-import "time"
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var cveIDPattern = regexp.MustCompile(`^CVE-\d{4}-\d{4,}$`)
+
+// dateLayouts are the formats dates are kept in: RFC 3339 and time.Time.String() from GitHub,
+// the NVD API's timestamps without zone and the MM/DD/YYYY dates of the NVD website
+var dateLayouts = []string{time.RFC3339, "2006-01-02 15:04:05 -0700 MST", "2006-01-02T15:04:05.000", "01/02/2006"}
+
+// ParseDate parses the PublishedDate and LastModified of a Vulnerability, whichever source set them
+func ParseDate(date string) (time.Time, error) {
+	date = strings.TrimSpace(date)
+	for _, layout := range dateLayouts {
+		if parsed, err := time.Parse(layout, date); err == nil {
+			return parsed, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unknown date format %q", date)
+}
+
+// IsCVEID reports whether id is a well-formed CVE ID such as CVE-2023-39325, IDs go into NVD URLs
+// and Redis keys as they are
+func IsCVEID(id string) bool {
+	return cveIDPattern.MatchString(id)
+}
+
+type Ecosystem string
+
+// Ecosystems as named by the GitHub Advisory Database GraphQL API
+const (
+	EcosystemGo       Ecosystem = "GO"
+	EcosystemNpm      Ecosystem = "NPM"
+	EcosystemPip      Ecosystem = "PIP"
+	EcosystemMaven    Ecosystem = "MAVEN"
+	EcosystemRubyGems Ecosystem = "RUBYGEMS"
+	EcosystemNuGet    Ecosystem = "NUGET"
+	EcosystemRust     Ecosystem = "RUST"
+	EcosystemComposer Ecosystem = "COMPOSER"
+	EcosystemPub      Ecosystem = "PUB"
+	// OS packages, GitHub doesn't cover them
+	EcosystemAlpine Ecosystem = "ALPINE"
+	EcosystemDebian Ecosystem = "DEBIAN"
+)
+
+// Sources a Vulnerability is reported by
+const (
+	SourceGitHub = "github"
+	SourceNVD    = "nvd"
+	SourceOSV    = "osv"
+	SourceVulnDB = "vulndb"
+	SourceAlpine = "alpine"
+	SourceDebian = "debian"
+)
+
+// Match states of a finding
+const (
+	MatchAffected = "affected"
+	// The advisory covers the package but whether its version is affected couldn't be told, e.g.
+	// because the version or the advisory range doesn't parse
+	MatchUnknown = "unknown"
+)
+
+// Module names the Go vulndb and OSV give the Go standard library and the go command
+const (
+	GoStdlib    = "stdlib"
+	GoToolchain = "toolchain"
+)
+
+type Package struct {
+	Name      string    `json:"name"`
+	Version   string    `json:"version"`
+	Ecosystem Ecosystem `json:"ecosystem"`
+	Indirect  bool      `json:"indirect"`
+	// Line of the manifest (e.g. go.mod) that declares the package, 0 when unknown
+	Line int `json:"line"`
+	// Reference of the SBOM component (CycloneDX bom-ref or SPDX SPDXID) the package comes from
+	BomRef string `json:"bomRef"`
+	// Release of the distribution of OS packages, e.g. v3.18 for Alpine or 12 for Debian
+	Release string `json:"release"`
+	// Layer of the container image that introduced the package and the file it was found in
+	Layer    string `json:"layer"`
+	Location string `json:"location"`
+}
+
+// PackageKey keys the findings of a package, e.g. NPM:lodash. Packages without an ecosystem are Go modules.
+func PackageKey(ecosystem Ecosystem, name string) string {
+	if ecosystem == "" {
+		ecosystem = EcosystemGo
+	}
+	return string(ecosystem) + ":" + name
+}
 
 type Vulnerability struct {
-	Name               string   `json:"name"`
-	Summary            string   `json:"summary"`
-	CVEID              string   `json:"CVEID"`
-	PublishedDate      string   `json:"publishDate"`
-	LastModified       string   `json:"lastModified"`
-	Description        string   `json:"description"`
-	VulnerableVersions []string `json:"vulnerableVersions"`
-	NVDScore           string   `json:"NVDScore"`
-	CNAScore           string   `json:"CNAScore"`
-	AffectedVersions   string   `json:"affectedVersions"`
-	PatchedVersions    string   `json:"patchedVersions"`
-	Severity           string   `json:"severity"`
+	Name               string     `json:"name"`
+	Ecosystem          Ecosystem  `json:"ecosystem"`
+	Version            string     `json:"version"`
+	Indirect           bool       `json:"indirect"`
+	Line               int        `json:"line"`
+	BomRef             string     `json:"bomRef"`
+	Layer              string     `json:"layer"`
+	Location           string     `json:"location"`
+	GHSAID             string     `json:"GHSAID"`
+	Aliases            []string   `json:"aliases"`
+	Summary            string     `json:"summary"`
+	CVEID              string     `json:"CVEID"`
+	PublishedDate      string     `json:"publishDate"`
+	LastModified       string     `json:"lastModified"`
+	Description        string     `json:"description"`
+	VulnerableVersions []string   `json:"vulnerableVersions"`
+	NVDScore           string     `json:"NVDScore"`
+	CNAScore           string     `json:"CNAScore"`
+	NVDVector          string     `json:"NVDVector"`
+	CNAVector          string     `json:"CNAVector"`
+	AffectedVersions   string     `json:"affectedVersions"`
+	PatchedVersions    string     `json:"patchedVersions"`
+	Severity           string     `json:"severity"`
+	References         []string   `json:"references"`
+	CPEMatches         []CPEMatch `json:"cpeMatches"`
+	Sources            []string   `json:"sources"`
+	// Packages and symbols of the module the vulnerability is in, as listed by the Go vulndb
+	Imports []VulnerableImport `json:"imports"`
+	// Set by the reachability analysis, empty when the source code wasn't analyzed
+	Reachability string   `json:"reachability"`
+	CallStack    []string `json:"callStack"`
+	// MatchAffected, or MatchUnknown with the reason the version couldn't be checked
+	Match       string `json:"match"`
+	MatchReason string `json:"matchReason"`
+}
+
+// VulnerableImport is a package of the vulnerable module and its vulnerable functions and methods,
+// e.g. {golang.org/x/net/http2 [Server.ServeConn]}. Without symbols the whole package is vulnerable.
+type VulnerableImport struct {
+	Path    string   `json:"path"`
+	Symbols []string `json:"symbols"`
+}
+
+// Reachability of a vulnerable symbol from the analyzed code
+const (
+	Reachable = "reachable"
+	// ModuleReachable is a finding without vulnerable symbols, e.g. a GitHub advisory, whose module
+	// is used by the code. The vulnerable functions may or may not be among the ones called.
+	ModuleReachable = "module reachable"
+	Unreachable     = "unreachable"
+	// NotAnalyzed is a finding that isn't called by the analyzed code but may be called by a
+	// dependency whose source isn't in the tree
+	NotAnalyzed = "not analyzed"
+)
+
+// CPEMatch is a CPE match of an NVD configuration: the product it names and the version range
+// it covers. Vulnerable is false for platforms that are only required to be present.
+type CPEMatch struct {
+	Criteria              string `json:"criteria"`
+	Vendor                string `json:"vendor"`
+	Product               string `json:"product"`
+	Version               string `json:"version"`
+	VersionStartIncluding string `json:"versionStartIncluding"`
+	VersionStartExcluding string `json:"versionStartExcluding"`
+	VersionEndIncluding   string `json:"versionEndIncluding"`
+	VersionEndExcluding   string `json:"versionEndExcluding"`
+	Vulnerable            bool   `json:"vulnerable"`
 }
 
 type GitHubVulnerabilityQueryResponse struct {
 	Data struct {
 		SecurityVulnerabilities struct {
-			Nodes []VulnerabilityNode `json:"nodes"`
+			Nodes    []VulnerabilityNode `json:"nodes"`
+			PageInfo PageInfo            `json:"pageInfo"`
 		} `json:"securityVulnerabilities"`
 	} `json:"data"`
+	// Set instead of or along with data when the query failed, e.g. on rate limits
+	Errors []GraphQLError `json:"errors"`
+}
+
+type GraphQLError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+type PageInfo struct {
+	EndCursor   string `json:"endCursor"`
+	HasNextPage bool   `json:"hasNextPage"`
 }
 
 type VulnerabilityNode struct {
@@ -30,13 +193,19 @@ type VulnerabilityNode struct {
 		Name string `json:"name"`
 	} `json:"package"`
 	Advisory struct {
+		GHSAID      string       `json:"ghsaId"`
+		Permalink   string       `json:"permalink"`
 		Summary     string       `json:"summary"`
 		Description string       `json:"description"`
 		Severity    string       `json:"severity"`
 		Identifiers []Identifier `json:"identifiers"`
-		PublishedAt time.Time    `json:"publishedAt"`
+		References  []struct {
+			URL string `json:"url"`
+		} `json:"references"`
+		PublishedAt time.Time `json:"publishedAt"`
 		CVSS        struct {
-			Score string `json:"score"`
+			Score        float64 `json:"score"`
+			VectorString string  `json:"vectorString"`
 		} `json:"cvss"`
 	} `json:"advisory"`
 	VulnerableVersionRange string `json:"vulnerableVersionRange"`
@@ -50,3 +219,69 @@ type Identifier struct {
 	Type  string `json:"type"`
 	Value string `json:"value"`
 }
+
+// NVDResponse is the body returned by the NVD CVE API 2.0 (https://services.nvd.nist.gov/rest/json/cves/2.0)
+type NVDResponse struct {
+	ResultsPerPage  int `json:"resultsPerPage"`
+	StartIndex      int `json:"startIndex"`
+	TotalResults    int `json:"totalResults"`
+	Vulnerabilities []struct {
+		CVE NVDCVE `json:"cve"`
+	} `json:"vulnerabilities"`
+}
+
+type NVDCVE struct {
+	ID           string `json:"id"`
+	Published    string `json:"published"`
+	LastModified string `json:"lastModified"`
+	Descriptions []struct {
+		Lang  string `json:"lang"`
+		Value string `json:"value"`
+	} `json:"descriptions"`
+	Metrics struct {
+		CvssMetricV40 []NVDCVSSMetric `json:"cvssMetricV40"`
+		CvssMetricV31 []NVDCVSSMetric `json:"cvssMetricV31"`
+		CvssMetricV30 []NVDCVSSMetric `json:"cvssMetricV30"`
+		CvssMetricV2  []NVDCVSSMetric `json:"cvssMetricV2"`
+	} `json:"metrics"`
+	Configurations []struct {
+		Nodes []struct {
+			Operator string        `json:"operator"`
+			Negate   bool          `json:"negate"`
+			CPEMatch []NVDCPEMatch `json:"cpeMatch"`
+		} `json:"nodes"`
+	} `json:"configurations"`
+	References []struct {
+		URL string `json:"url"`
+	} `json:"references"`
+}
+
+type NVDCVSSMetric struct {
+	Source   string `json:"source"`
+	Type     string `json:"type"`
+	CvssData struct {
+		Version      string  `json:"version"`
+		VectorString string  `json:"vectorString"`
+		BaseScore    float64 `json:"baseScore"`
+		BaseSeverity string  `json:"baseSeverity"`
+	} `json:"cvssData"`
+	// CVSS v2 metrics have their severity next to cvssData instead of in it
+	BaseSeverity string `json:"baseSeverity"`
+}
+
+// Severity is the base severity of the metric, wherever its CVSS version keeps it
+func (metric NVDCVSSMetric) Severity() string {
+	if metric.CvssData.BaseSeverity != "" {
+		return metric.CvssData.BaseSeverity
+	}
+	return metric.BaseSeverity
+}
+
+type NVDCPEMatch struct {
+	Vulnerable            bool   `json:"vulnerable"`
+	Criteria              string `json:"criteria"`
+	VersionStartIncluding string `json:"versionStartIncluding"`
+	VersionStartExcluding string `json:"versionStartExcluding"`
+	VersionEndIncluding   string `json:"versionEndIncluding"`
+	VersionEndExcluding   string `json:"versionEndExcluding"`
+}
@@ -0,0 +1,203 @@
+package versions
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Token types of apk versions, in the order they may follow each other, as in apk-tools
+const (
+	apkInvalid = iota - 1
+	apkDigitOrZero
+	apkDigit
+	apkLetter
+	apkSuffix
+	apkSuffixNumber
+	apkRevision
+	apkEnd
+)
+
+var (
+	// Pre-release suffixes sort before the release, post-release ones after it
+	apkPreSuffixes  = []string{"alpha", "beta", "pre", "rc"}
+	apkPostSuffixes = []string{"cvs", "svn", "git", "hg", "p"}
+)
+
+// apkTokenizer walks an apk version such as 1.2.3a_rc1_p2-r4 the way apk-tools does
+type apkTokenizer struct {
+	version string
+	kind    int
+}
+
+func newAPKTokenizer(version string) *apkTokenizer {
+	return &apkTokenizer{version: version, kind: apkDigit}
+}
+
+// next consumes the current token and returns its value
+func (t *apkTokenizer) next() int64 {
+	if t.version == "" {
+		t.kind = apkEnd
+		return 0
+	}
+
+	var value int64
+	i := 0
+	following := apkInvalid
+
+	switch t.kind {
+	case apkDigitOrZero:
+		// Leading zeros make the component sort like a decimal fraction, 1.01 < 1.1
+		if t.version[0] == '0' {
+			for i < len(t.version) && t.version[i] == '0' {
+				i++
+			}
+			following = apkDigit
+			value = int64(-i)
+			break
+		}
+		fallthrough
+	case apkDigit, apkSuffixNumber, apkRevision:
+		for i < len(t.version) && isDigit(t.version[i]) {
+			value = value*10 + int64(t.version[i]-'0')
+			i++
+		}
+	case apkLetter:
+		value = int64(t.version[0])
+		i = 1
+	case apkSuffix:
+		found := false
+		for index, suffix := range apkPreSuffixes {
+			if strings.HasPrefix(t.version, suffix) {
+				value, i, found = int64(index-len(apkPreSuffixes)), len(suffix), true
+				break
+			}
+		}
+		for index, suffix := range apkPostSuffixes {
+			if !found && strings.HasPrefix(t.version, suffix) {
+				value, i, found = int64(index), len(suffix), true
+				break
+			}
+		}
+		if !found {
+			t.kind = apkInvalid
+			return -1
+		}
+	default:
+		t.kind = apkInvalid
+		return -1
+	}
+
+	t.version = t.version[i:]
+	switch {
+	case t.version == "":
+		t.kind = apkEnd
+	case following != apkInvalid:
+		t.kind = following
+	default:
+		t.advance()
+	}
+
+	return value
+}
+
+// advance reads the separator of the next token and works out its type
+func (t *apkTokenizer) advance() {
+	next := apkInvalid
+	c := t.version[0]
+
+	switch {
+	case (t.kind == apkDigit || t.kind == apkDigitOrZero) && c >= 'a' && c <= 'z':
+		next = apkLetter
+	case t.kind == apkLetter && isDigit(c):
+		next = apkDigit
+	case t.kind == apkSuffix && isDigit(c):
+		next = apkSuffixNumber
+	default:
+		switch c {
+		case '.':
+			next = apkDigitOrZero
+		case '_':
+			next = apkSuffix
+		case '-':
+			if strings.HasPrefix(t.version, "-r") {
+				next = apkRevision
+				t.version = t.version[1:]
+			}
+		}
+		t.version = t.version[1:]
+	}
+
+	// Tokens only go forward, 1.2_rc1.3 or 1.2-r1_p1 are invalid
+	if next < t.kind && !(next == apkDigitOrZero && t.kind == apkDigit) &&
+		!(next == apkSuffix && t.kind == apkSuffixNumber) && !(next == apkDigit && t.kind == apkLetter) {
+		next = apkInvalid
+	}
+	t.kind = next
+}
+
+// validAPK walks the whole version to make sure apk-tools would accept it
+func validAPK(version string) bool {
+	if version == "" || !isDigit(version[0]) {
+		return false
+	}
+
+	tokenizer := newAPKTokenizer(version)
+	for tokenizer.kind != apkEnd {
+		if tokenizer.next(); tokenizer.kind == apkInvalid {
+			return false
+		}
+	}
+	return true
+}
+
+// compareAPK orders Alpine package versions like `apk version -t`: numeric components, an
+// optional letter, _alpha, _beta, _pre and _rc pre-releases, _cvs, _svn, _git, _hg and _p
+// post-releases, then the -r package revision
+func compareAPK(a string, b string) (int, error) {
+	for _, version := range []string{a, b} {
+		if !validAPK(version) {
+			return 0, fmt.Errorf("invalid apk version %q", version)
+		}
+	}
+
+	first, second := newAPKTokenizer(a), newAPKTokenizer(b)
+	var x, y int64
+	for first.kind == second.kind && first.kind != apkEnd && first.kind != apkInvalid && x == y {
+		x = first.next()
+		y = second.next()
+	}
+
+	switch {
+	case x < y:
+		return -1, nil
+	case x > y:
+		return 1, nil
+	case first.kind == second.kind:
+		return 0, nil
+	}
+
+	// The common components are equal, the longer version is the higher one unless what follows
+	// is a pre-release suffix
+	if first.kind == apkSuffix {
+		if suffix := *first; suffix.next() < 0 {
+			return -1, nil
+		}
+	}
+	if second.kind == apkSuffix {
+		if suffix := *second; suffix.next() < 0 {
+			return 1, nil
+		}
+	}
+
+	switch {
+	case first.kind > second.kind:
+		return -1, nil
+	case first.kind < second.kind:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
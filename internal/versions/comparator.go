@@ -0,0 +1,52 @@
+package versions
+
+import (
+	types "khazande/internal/types"
+)
+
+// VersionComparator orders the versions of an ecosystem. Compare returns -1, 0 or +1 depending on
+// whether a is lower, equal or higher than b and fails on versions the ecosystem doesn't accept.
+type VersionComparator interface {
+	Compare(a string, b string) (int, error)
+}
+
+// ComparatorFunc adapts an ordinary function to a VersionComparator
+type ComparatorFunc func(a string, b string) (int, error)
+
+func (f ComparatorFunc) Compare(a string, b string) (int, error) {
+	return f(a, b)
+}
+
+var comparators = map[types.Ecosystem]VersionComparator{
+	types.EcosystemGo:       ComparatorFunc(compareGo),
+	types.EcosystemNpm:      ComparatorFunc(compareSemver),
+	types.EcosystemRust:     ComparatorFunc(compareSemver),
+	types.EcosystemComposer: ComparatorFunc(compareSemver),
+	types.EcosystemPub:      ComparatorFunc(compareSemver),
+	types.EcosystemPip:      ComparatorFunc(comparePEP440),
+	types.EcosystemMaven:    ComparatorFunc(compareMaven),
+	types.EcosystemRubyGems: ComparatorFunc(compareRubyGems),
+	types.EcosystemNuGet:    ComparatorFunc(compareNuGet),
+	types.EcosystemAlpine:   ComparatorFunc(compareAPK),
+	types.EcosystemDebian:   ComparatorFunc(compareDpkg),
+}
+
+// fallback orders the versions of ecosystems without a comparator of their own
+var fallback = ComparatorFunc(func(a string, b string) (int, error) {
+	return compareDotted(a, b), nil
+})
+
+// Register replaces the comparator of an ecosystem or adds one for a new ecosystem. It isn't safe
+// to call while versions are being compared, register comparators at startup.
+func Register(ecosystem types.Ecosystem, comparator VersionComparator) {
+	comparators[ecosystem] = comparator
+}
+
+// ComparatorFor returns the comparator of the ecosystem, unknown and empty ecosystems get the
+// dotted version ordering
+func ComparatorFor(ecosystem types.Ecosystem) VersionComparator {
+	if comparator, ok := comparators[ecosystem]; ok {
+		return comparator
+	}
+	return fallback
+}
@@ -0,0 +1,173 @@
+package versions
+
+import (
+	"testing"
+
+	types "khazande/internal/types"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		ecosystem types.Ecosystem
+		a         string
+		b         string
+		want      int
+	}{
+		// Go modules, pseudo-versions order by their timestamp and sort before the release they precede
+		{types.EcosystemGo, "v1.2.3", "v1.2.4", -1},
+		{types.EcosystemGo, "1.2.3", "v1.2.3", 0},
+		{types.EcosystemGo, "v0.0.0-20230101000000-abcdefabcdef", "v0.0.0-20230201000000-abcdefabcdef", -1},
+		{types.EcosystemGo, "v1.2.4-0.20230101000000-abcdefabcdef", "v1.2.3", 1},
+		{types.EcosystemGo, "v1.2.4-0.20230101000000-abcdefabcdef", "v1.2.4", -1},
+		{types.EcosystemGo, "v2.0.0+incompatible", "v2.0.0", 0},
+		{types.EcosystemGo, "v2.0.0+incompatible", "v2.0.1", -1},
+		{types.EcosystemGo, "v1.0.0-rc.1", "v1.0.0", -1},
+
+		// PEP 440, epoch, release, pre, post, dev and local in that order
+		{types.EcosystemPip, "1.0", "1.0.0", 0},
+		{types.EcosystemPip, "1.0.post1", "1.0", 1},
+		{types.EcosystemPip, "2.0rc1", "2.0", -1},
+		{types.EcosystemPip, "2.0rc1", "2.0b3", 1},
+		{types.EcosystemPip, "2.0a1", "2.0b1", -1},
+		{types.EcosystemPip, "1.0.dev1", "1.0a1", -1},
+		{types.EcosystemPip, "1.0a1.dev1", "1.0a1", -1},
+		{types.EcosystemPip, "1.0.post1.dev1", "1.0", 1},
+		{types.EcosystemPip, "1.0.post1.dev1", "1.0.post1", -1},
+		{types.EcosystemPip, "1!0.1", "2.0", 1},
+		{types.EcosystemPip, "1.0+local.1", "1.0", 1},
+		{types.EcosystemPip, "1.0+local.2", "1.0+local.10", -1},
+		{types.EcosystemPip, "1.0+abc", "1.0+1", -1},
+		{types.EcosystemPip, "1.0-1", "1.0.post1", 0},
+		{types.EcosystemPip, "1.0alpha1", "1.0a1", 0},
+
+		// Maven qualifiers, alpha < beta < milestone < rc < snapshot < release < sp < unknown ones
+		{types.EcosystemMaven, "1.0-alpha-1", "1.0-beta-1", -1},
+		{types.EcosystemMaven, "1.0-beta-1", "1-m1", -1},
+		{types.EcosystemMaven, "1-m1", "1-rc1", -1},
+		{types.EcosystemMaven, "1.0-rc1", "1.0-SNAPSHOT", -1},
+		{types.EcosystemMaven, "1.0-SNAPSHOT", "1.0", -1},
+		{types.EcosystemMaven, "1.0", "1.0-sp1", -1},
+		{types.EcosystemMaven, "1.0-sp", "1.0-foo", -1},
+		{types.EcosystemMaven, "1.0-cr1", "1.0-rc1", 0},
+		{types.EcosystemMaven, "1.0.Final", "1.0", 0},
+		{types.EcosystemMaven, "1.0-ga", "1.0.0", 0},
+		{types.EcosystemMaven, "1.0a1", "1.0-alpha-1", 0},
+		{types.EcosystemMaven, "1.10", "1.9", 1},
+
+		// RubyGems, a letter segment makes a prerelease that sorts before the release
+		{types.EcosystemRubyGems, "1.0.a", "1.0", -1},
+		{types.EcosystemRubyGems, "1.0.0.rc1", "1.0.0.beta2", 1},
+		{types.EcosystemRubyGems, "1.0.0.beta2", "1.0.0.beta10", -1},
+		{types.EcosystemRubyGems, "1.0-1", "1.0", -1},
+		{types.EcosystemRubyGems, "5.2.4.3", "5.2.4.10", -1},
+		{types.EcosystemRubyGems, "1.0.0", "1", 0},
+
+		// NuGet, up to four numbers and case-insensitive dot separated labels
+		{types.EcosystemNuGet, "1.0", "1.0.0.0", 0},
+		{types.EcosystemNuGet, "4.3.0.1", "4.3.0", 1},
+		{types.EcosystemNuGet, "1.0.0-Beta", "1.0.0-beta", 0},
+		{types.EcosystemNuGet, "1.0.0-beta.2", "1.0.0-beta.10", -1},
+		{types.EcosystemNuGet, "1.0.0-RC.1", "1.0.0-beta.1", 1},
+		{types.EcosystemNuGet, "1.0.0-beta", "1.0.0", -1},
+		{types.EcosystemNuGet, "1.0.0+build.1", "1.0.0", 0},
+
+		// apk, suffixes before or after the release and -rN revisions
+		{types.EcosystemAlpine, "1.2_rc1", "1.2", -1},
+		{types.EcosystemAlpine, "1.2_alpha1", "1.2_beta1", -1},
+		{types.EcosystemAlpine, "1.2_p1", "1.2", 1},
+		{types.EcosystemAlpine, "1.2.1", "1.2a", 1},
+		{types.EcosystemAlpine, "1.2-r10", "1.2-r9", 1},
+		{types.EcosystemAlpine, "1.01", "1.1", -1},
+		{types.EcosystemAlpine, "1.2", "1.2.0", -1},
+
+		// dpkg, epochs first, ~ before anything even the end of the version
+		{types.EcosystemDebian, "1.0~rc1", "1.0", -1},
+		{types.EcosystemDebian, "1.0~~", "1.0~", -1},
+		{types.EcosystemDebian, "1:0.1", "2.0", 1},
+		{types.EcosystemDebian, "3.0.11-1~deb12u2", "3.0.11-1", -1},
+		{types.EcosystemDebian, "3.0.11-1", "3.0.11-2", -1},
+		{types.EcosystemDebian, "1.01", "1.1", 0},
+		{types.EcosystemDebian, "1.0+dfsg", "1.0", 1},
+
+		// npm and the other semver ecosystems
+		{types.EcosystemNpm, "1.0.0-alpha", "1.0.0", -1},
+		{types.EcosystemNpm, "1.10.0", "1.9.0", 1},
+	}
+
+	for _, test := range tests {
+		got, err := Compare(test.ecosystem, test.a, test.b)
+		if err != nil {
+			t.Errorf("Compare(%s, %q, %q) failed: %v", test.ecosystem, test.a, test.b, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("Compare(%s, %q, %q) = %d, want %d", test.ecosystem, test.a, test.b, got, test.want)
+		}
+
+		// The ordering has to be antisymmetric
+		if reversed, _ := Compare(test.ecosystem, test.b, test.a); reversed != -test.want {
+			t.Errorf("Compare(%s, %q, %q) = %d, want %d", test.ecosystem, test.b, test.a, reversed, -test.want)
+		}
+	}
+}
+
+func TestCompareInvalid(t *testing.T) {
+	tests := []struct {
+		ecosystem types.Ecosystem
+		version   string
+	}{
+		{types.EcosystemGo, "latest"},
+		{types.EcosystemPip, "foo"},
+		{types.EcosystemRubyGems, "a.b"},
+		{types.EcosystemNuGet, "1.2.3.4.5"},
+		{types.EcosystemAlpine, "1.2_foo"},
+		{types.EcosystemDebian, "a1.0"},
+		{types.EcosystemDebian, "x:1.0"},
+	}
+
+	for _, test := range tests {
+		if _, err := Compare(test.ecosystem, test.version, "1.0"); err == nil {
+			t.Errorf("Compare(%s, %q, \"1.0\") succeeded, want an error", test.ecosystem, test.version)
+		}
+	}
+}
+
+func TestInRange(t *testing.T) {
+	tests := []struct {
+		ecosystem    types.Ecosystem
+		version      string
+		versionRange string
+		want         bool
+	}{
+		{types.EcosystemGo, "v1.2.3", ">= 1.0.0, < 1.2.4", true},
+		{types.EcosystemGo, "v1.2.4", ">= 1.0.0, < 1.2.4", false},
+		{types.EcosystemGo, "v1.2.4-0.20230101000000-abcdefabcdef", "< 1.2.4", true},
+		{types.EcosystemGo, "v2.0.0+incompatible", "= 2.0.0", true},
+		{types.EcosystemPip, "2.0rc1", "< 2.0", true},
+		{types.EcosystemPip, "1.0.post1", "<= 1.0", false},
+		{types.EcosystemMaven, "2.15.0-rc1", ">= 2.0-beta9, < 2.15.0", true},
+		{types.EcosystemMaven, "1.0-sp1", "<= 1.0", false},
+		{types.EcosystemRubyGems, "6.0.0.rc1", ">= 5.2.0, < 6.0.0", true},
+		{types.EcosystemNuGet, "4.3.0.1", "> 4.3.0", true},
+		{types.EcosystemNuGet, "1.0.0-RC1", "< 1.0.0-rc2", true},
+		{types.EcosystemAlpine, "3.1.2-r0", "< 3.1.2-r1", true},
+		{types.EcosystemDebian, "3.0.11-1~deb12u2", "< 3.0.11-1~deb12u3", true},
+		{types.EcosystemDebian, "1:1.0", "< 2.0", false},
+		{types.EcosystemNpm, "4.17.20", ">= 4.0.0, != 4.17.20", false},
+	}
+
+	for _, test := range tests {
+		got, err := InRange(test.ecosystem, test.version, test.versionRange)
+		if err != nil {
+			t.Errorf("InRange(%s, %q, %q) failed: %v", test.ecosystem, test.version, test.versionRange, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("InRange(%s, %q, %q) = %t, want %t", test.ecosystem, test.version, test.versionRange, got, test.want)
+		}
+	}
+
+	if _, err := InRange(types.EcosystemPip, "foo", "< 1.0"); err == nil {
+		t.Errorf("InRange(%s, \"foo\", \"< 1.0\") succeeded, want an error", types.EcosystemPip)
+	}
+}
@@ -0,0 +1,137 @@
+package versions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// dpkgVersion is a Debian version, [epoch:]upstream_version[-debian_revision]
+type dpkgVersion struct {
+	epoch    uint64
+	upstream string
+	revision string
+}
+
+func parseDpkg(version string) (dpkgVersion, error) {
+	version = strings.TrimSpace(version)
+	parsed := dpkgVersion{upstream: version}
+
+	if epoch, rest, ok := strings.Cut(version, ":"); ok {
+		value, err := strconv.ParseUint(epoch, 10, 64)
+		if err != nil {
+			return dpkgVersion{}, fmt.Errorf("invalid epoch in dpkg version %q", version)
+		}
+		parsed.epoch, parsed.upstream = value, rest
+	}
+
+	// The revision follows the last hyphen, the upstream version may contain hyphens itself
+	if index := strings.LastIndex(parsed.upstream, "-"); index != -1 {
+		parsed.upstream, parsed.revision = parsed.upstream[:index], parsed.upstream[index+1:]
+	}
+
+	if parsed.upstream == "" || !isDigit(parsed.upstream[0]) {
+		return dpkgVersion{}, fmt.Errorf("invalid dpkg version %q, the upstream version must start with a digit", version)
+	}
+
+	return parsed, nil
+}
+
+// compareDpkg orders Debian package versions like `dpkg --compare-versions`: epochs first, then
+// the upstream version and the revision, where ~ sorts before anything, even the end of the version
+func compareDpkg(a string, b string) (int, error) {
+	first, err := parseDpkg(a)
+	if err != nil {
+		return 0, err
+	}
+	second, err := parseDpkg(b)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case first.epoch < second.epoch:
+		return -1, nil
+	case first.epoch > second.epoch:
+		return 1, nil
+	}
+
+	if result := compareDpkgPart(first.upstream, second.upstream); result != 0 {
+		return result, nil
+	}
+	return compareDpkgPart(first.revision, second.revision), nil
+}
+
+// dpkgOrder ranks the characters of the non-digit parts: ~ first, then the end of the part,
+// letters and the other characters
+func dpkgOrder(c byte) int {
+	switch {
+	case c == '~':
+		return -1
+	case isDigit(c):
+		return 0
+	case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		return int(c)
+	default:
+		return int(c) + 256
+	}
+}
+
+// compareDpkgPart compares alternating non-digit and digit runs, as dpkg's verrevcmp does
+func compareDpkgPart(a string, b string) int {
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for (i < len(a) && !isDigit(a[i])) || (j < len(b) && !isDigit(b[j])) {
+			var x, y int
+			if i < len(a) {
+				x = dpkgOrder(a[i])
+			}
+			if j < len(b) {
+				y = dpkgOrder(b[j])
+			}
+			if x != y {
+				return sign(x - y)
+			}
+			i++
+			j++
+		}
+
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+
+		difference := 0
+		for i < len(a) && isDigit(a[i]) && j < len(b) && isDigit(b[j]) {
+			if difference == 0 {
+				difference = int(a[i]) - int(b[j])
+			}
+			i++
+			j++
+		}
+		// The longer run of digits is the larger number
+		if i < len(a) && isDigit(a[i]) {
+			return 1
+		}
+		if j < len(b) && isDigit(b[j]) {
+			return -1
+		}
+		if difference != 0 {
+			return sign(difference)
+		}
+	}
+
+	return 0
+}
+
+func sign(value int) int {
+	switch {
+	case value < 0:
+		return -1
+	case value > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,31 @@
+package versions
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// compareGo orders Go module versions like the go command. Pseudo-versions such as
+// v0.0.0-20230101120000-abcdef123456 are pre-releases of the version they follow and the
+// +incompatible suffix of major versions without a go.mod is build metadata, ignored by the
+// ordering. OSV and GitHub write versions without the v prefix, it is optional.
+func compareGo(a string, b string) (int, error) {
+	first, second := goCanonical(a), goCanonical(b)
+	for i, version := range []string{first, second} {
+		if !semver.IsValid(version) {
+			return 0, fmt.Errorf("invalid Go module version %q", []string{a, b}[i])
+		}
+	}
+
+	return semver.Compare(first, second), nil
+}
+
+func goCanonical(version string) string {
+	version = strings.TrimSpace(version)
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	return version
+}
@@ -0,0 +1,241 @@
+package versions
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Qualifiers Maven knows the order of, the empty one being the release. Unknown qualifiers come
+// after all of them, alphabetically.
+var mavenQualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+var mavenAliases = map[string]string{
+	"ga":      "",
+	"final":   "",
+	"release": "",
+	"cr":      "rc",
+}
+
+var mavenReleaseIndex = strconv.Itoa(len(mavenQualifiers) - 2)
+
+// mavenItem is a part of a parsed Maven version, as in Maven's ComparableVersion. Comparing with
+// nil compares with a missing item.
+type mavenItem interface {
+	compare(other mavenItem) int
+	isNull() bool
+}
+
+type mavenInt struct {
+	value *big.Int
+}
+
+type mavenString struct {
+	value string
+}
+
+type mavenList struct {
+	items []mavenItem
+}
+
+func (item mavenInt) isNull() bool {
+	return item.value.Sign() == 0
+}
+
+func (item mavenInt) compare(other mavenItem) int {
+	switch other := other.(type) {
+	case nil:
+		if item.isNull() {
+			return 0
+		}
+		return 1
+	case mavenInt:
+		return item.value.Cmp(other.value)
+	default:
+		// 1.1 > 1-sp and 1.1 > 1-1
+		return 1
+	}
+}
+
+func newMavenString(value string, followedByDigit bool) mavenString {
+	if followedByDigit && len(value) == 1 {
+		// a1, b1 and m1 stand for alpha-1, beta-1 and milestone-1
+		switch value {
+		case "a":
+			value = "alpha"
+		case "b":
+			value = "beta"
+		case "m":
+			value = "milestone"
+		}
+	}
+	if alias, ok := mavenAliases[value]; ok {
+		value = alias
+	}
+	return mavenString{value: value}
+}
+
+// comparableQualifier turns the qualifier into a string ordering like the qualifiers do
+func (item mavenString) comparableQualifier() string {
+	for index, qualifier := range mavenQualifiers {
+		if qualifier == item.value {
+			return strconv.Itoa(index)
+		}
+	}
+	return strconv.Itoa(len(mavenQualifiers)) + "-" + item.value
+}
+
+func (item mavenString) isNull() bool {
+	return item.comparableQualifier() == mavenReleaseIndex
+}
+
+func (item mavenString) compare(other mavenItem) int {
+	switch other := other.(type) {
+	case nil:
+		// 1-rc < 1, 1-sp > 1
+		return strings.Compare(item.comparableQualifier(), mavenReleaseIndex)
+	case mavenString:
+		return strings.Compare(item.comparableQualifier(), other.comparableQualifier())
+	default:
+		// 1.any < 1.1 and 1.any < 1-1
+		return -1
+	}
+}
+
+func (item *mavenList) isNull() bool {
+	return len(item.items) == 0
+}
+
+func (item *mavenList) compare(other mavenItem) int {
+	switch other := other.(type) {
+	case nil:
+		if len(item.items) == 0 {
+			return 0
+		}
+		return item.items[0].compare(nil)
+	case mavenInt:
+		return -1
+	case mavenString:
+		return 1
+	case *mavenList:
+		for i := 0; i < len(item.items) || i < len(other.items); i++ {
+			var left, right mavenItem
+			if i < len(item.items) {
+				left = item.items[i]
+			}
+			if i < len(other.items) {
+				right = other.items[i]
+			}
+
+			var result int
+			switch {
+			case left == nil && right == nil:
+			case left == nil:
+				result = -right.compare(left)
+			default:
+				result = left.compare(right)
+			}
+			if result != 0 {
+				return result
+			}
+		}
+	}
+	return 0
+}
+
+// normalize drops the trailing null items, 1.0.0 is 1 and 1-ga is 1
+func (item *mavenList) normalize() {
+	for i := len(item.items) - 1; i >= 0; i-- {
+		last := item.items[i]
+		if last.isNull() {
+			item.items = append(item.items[:i], item.items[i+1:]...)
+			continue
+		}
+		if _, ok := last.(*mavenList); !ok {
+			break
+		}
+	}
+}
+
+func parseMavenItem(digit bool, value string) mavenItem {
+	if digit {
+		number, ok := new(big.Int).SetString(strings.TrimLeft(value, "0"), 10)
+		if !ok {
+			number = new(big.Int)
+		}
+		return mavenInt{value: number}
+	}
+	return newMavenString(value, false)
+}
+
+// parseMaven splits a version into nested lists: dots separate the items of a list, hyphens and
+// transitions between digits and letters start a sub-list
+func parseMaven(version string) *mavenList {
+	version = strings.ToLower(strings.TrimSpace(version))
+
+	root := &mavenList{}
+	list := root
+	stack := []*mavenList{root}
+	digit := false
+	start := 0
+
+	startList := func() {
+		sub := &mavenList{}
+		list.items = append(list.items, sub)
+		list = sub
+		stack = append(stack, sub)
+	}
+
+	for i := 0; i < len(version); i++ {
+		c := version[i]
+		switch {
+		case c == '.':
+			if i == start {
+				list.items = append(list.items, mavenInt{value: new(big.Int)})
+			} else {
+				list.items = append(list.items, parseMavenItem(digit, version[start:i]))
+			}
+			start = i + 1
+		case c == '-':
+			if i == start {
+				list.items = append(list.items, mavenInt{value: new(big.Int)})
+			} else {
+				list.items = append(list.items, parseMavenItem(digit, version[start:i]))
+			}
+			start = i + 1
+			startList()
+		case isDigit(c):
+			if !digit && i > start {
+				// 1.0alpha1 is 1.0-alpha-1
+				list.items = append(list.items, newMavenString(version[start:i], true))
+				start = i
+				startList()
+			}
+			digit = true
+		default:
+			if digit && i > start {
+				list.items = append(list.items, parseMavenItem(true, version[start:i]))
+				start = i
+				startList()
+			}
+			digit = false
+		}
+	}
+
+	if len(version) > start {
+		list.items = append(list.items, parseMavenItem(digit, version[start:]))
+	}
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		stack[i].normalize()
+	}
+
+	return root
+}
+
+// compareMaven orders Maven versions like Maven's ComparableVersion: numbers numerically, the
+// alpha, beta, milestone, rc and snapshot qualifiers before the release and sp after it. Every
+// string is a valid Maven version, the comparison never fails.
+func compareMaven(a string, b string) (int, error) {
+	return sign(parseMaven(a).compare(parseMaven(b))), nil
+}
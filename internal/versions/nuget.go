@@ -0,0 +1,103 @@
+package versions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// nugetVersion is a NuGet version, up to four numeric parts followed by SemVer 2 pre-release labels
+type nugetVersion struct {
+	parts      [4]uint64
+	prerelease []string
+}
+
+func parseNuGet(version string) (nugetVersion, error) {
+	var parsed nugetVersion
+
+	release, _, _ := strings.Cut(strings.TrimSpace(version), "+")
+	release, prerelease, hasPrerelease := strings.Cut(release, "-")
+
+	numbers := strings.Split(release, ".")
+	if len(numbers) > 4 {
+		return nugetVersion{}, fmt.Errorf("invalid NuGet version %q, at most four numeric parts are allowed", version)
+	}
+	for i, number := range numbers {
+		value, err := strconv.ParseUint(number, 10, 64)
+		if err != nil {
+			return nugetVersion{}, fmt.Errorf("invalid NuGet version %q", version)
+		}
+		parsed.parts[i] = value
+	}
+
+	if hasPrerelease {
+		if prerelease == "" {
+			return nugetVersion{}, fmt.Errorf("invalid NuGet version %q, empty pre-release label", version)
+		}
+		parsed.prerelease = strings.Split(prerelease, ".")
+	}
+
+	return parsed, nil
+}
+
+// compareNuGet orders NuGet versions: 1.0 and 1.0.0.0 are equal, pre-releases come before the
+// release, their labels are compared case insensitively and build metadata is ignored
+func compareNuGet(a string, b string) (int, error) {
+	first, err := parseNuGet(a)
+	if err != nil {
+		return 0, err
+	}
+	second, err := parseNuGet(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range first.parts {
+		switch {
+		case first.parts[i] < second.parts[i]:
+			return -1, nil
+		case first.parts[i] > second.parts[i]:
+			return 1, nil
+		}
+	}
+
+	switch {
+	case len(first.prerelease) == 0 && len(second.prerelease) == 0:
+		return 0, nil
+	case len(first.prerelease) == 0:
+		return 1, nil
+	case len(second.prerelease) == 0:
+		return -1, nil
+	}
+
+	for i := 0; i < len(first.prerelease) && i < len(second.prerelease); i++ {
+		if result := comparePrereleaseLabel(first.prerelease[i], second.prerelease[i]); result != 0 {
+			return result, nil
+		}
+	}
+
+	return sign(len(first.prerelease) - len(second.prerelease)), nil
+}
+
+// comparePrereleaseLabel orders SemVer pre-release identifiers: numbers numerically and before
+// alphanumeric labels, which are compared case insensitively
+func comparePrereleaseLabel(x string, y string) int {
+	xNumber, yNumber := isNumber(x), isNumber(y)
+	switch {
+	case xNumber && yNumber:
+		first, _ := strconv.ParseUint(x, 10, 64)
+		second, _ := strconv.ParseUint(y, 10, 64)
+		switch {
+		case first < second:
+			return -1
+		case first > second:
+			return 1
+		}
+		return 0
+	case xNumber:
+		return -1
+	case yNumber:
+		return 1
+	}
+	return strings.Compare(strings.ToLower(x), strings.ToLower(y))
+}
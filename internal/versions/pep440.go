@@ -0,0 +1,198 @@
+package versions
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pep440Pattern is the permissive version pattern of https://peps.python.org/pep-0440/, which also
+// accepts the spellings normalized by pip such as 1.0-1, 1.0alpha1 or 1.0.dev
+var pep440Pattern = regexp.MustCompile(`^v?` +
+	`(?:(\d+)!)?` +
+	`(\d+(?:\.\d+)*)` +
+	`(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?(\d+)?)?` +
+	`(?:-(\d+)|[-_.]?(post|rev|r)[-_.]?(\d+)?)?` +
+	`(?:[-_.]?(dev)[-_.]?(\d+)?)?` +
+	`(?:\+([a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`)
+
+// Ranks of the pre-release phases, a release without pre-release sorts after all of them unless
+// it only is a development release
+const (
+	pep440DevOnly = iota - 1
+	pep440Alpha
+	pep440Beta
+	pep440RC
+	pep440Final
+)
+
+type pep440Version struct {
+	epoch   uint64
+	release []uint64
+	preRank int
+	pre     uint64
+	hasPost bool
+	post    uint64
+	hasDev  bool
+	dev     uint64
+	local   []string
+}
+
+func parsePEP440(version string) (pep440Version, error) {
+	match := pep440Pattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(version)))
+	if match == nil {
+		return pep440Version{}, fmt.Errorf("invalid PEP 440 version %q", version)
+	}
+
+	number := func(value string) uint64 {
+		// The pattern only lets digits through, overflows are the only failure and saturate
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil && value != "" {
+			return ^uint64(0)
+		}
+		return parsed
+	}
+
+	parsed := pep440Version{epoch: number(match[1]), preRank: pep440Final}
+
+	for _, segment := range strings.Split(match[2], ".") {
+		parsed.release = append(parsed.release, number(segment))
+	}
+	// Trailing zeros don't matter, 1.0 is 1.0.0
+	for len(parsed.release) > 1 && parsed.release[len(parsed.release)-1] == 0 {
+		parsed.release = parsed.release[:len(parsed.release)-1]
+	}
+
+	switch match[3] {
+	case "a", "alpha":
+		parsed.preRank = pep440Alpha
+	case "b", "beta":
+		parsed.preRank = pep440Beta
+	case "c", "rc", "pre", "preview":
+		parsed.preRank = pep440RC
+	}
+	parsed.pre = number(match[4])
+
+	switch {
+	case match[5] != "":
+		parsed.hasPost, parsed.post = true, number(match[5])
+	case match[6] != "":
+		parsed.hasPost, parsed.post = true, number(match[7])
+	}
+
+	if match[8] != "" {
+		parsed.hasDev, parsed.dev = true, number(match[9])
+		// 1.0.dev1 comes before 1.0a1, 1.0.post1.dev1 still after 1.0
+		if parsed.preRank == pep440Final && !parsed.hasPost {
+			parsed.preRank = pep440DevOnly
+		}
+	}
+
+	if match[10] != "" {
+		parsed.local = strings.FieldsFunc(match[10], func(r rune) bool {
+			return r == '-' || r == '_' || r == '.'
+		})
+	}
+
+	return parsed, nil
+}
+
+// comparePEP440 orders Python package versions as pip does: epoch, release, pre-release,
+// post-release, development release, then local version label
+func comparePEP440(a string, b string) (int, error) {
+	first, err := parsePEP440(a)
+	if err != nil {
+		return 0, err
+	}
+	second, err := parsePEP440(b)
+	if err != nil {
+		return 0, err
+	}
+
+	if result := compareUint(first.epoch, second.epoch); result != 0 {
+		return result, nil
+	}
+
+	for i := 0; i < len(first.release) || i < len(second.release); i++ {
+		var x, y uint64
+		if i < len(first.release) {
+			x = first.release[i]
+		}
+		if i < len(second.release) {
+			y = second.release[i]
+		}
+		if result := compareUint(x, y); result != 0 {
+			return result, nil
+		}
+	}
+
+	if result := sign(first.preRank - second.preRank); result != 0 {
+		return result, nil
+	}
+	if result := compareUint(first.pre, second.pre); result != 0 {
+		return result, nil
+	}
+
+	// A post-release comes after the release itself
+	if first.hasPost != second.hasPost {
+		return boolOrder(first.hasPost), nil
+	}
+	if result := compareUint(first.post, second.post); result != 0 {
+		return result, nil
+	}
+
+	// A development release comes before the release it leads to
+	if first.hasDev != second.hasDev {
+		return -boolOrder(first.hasDev), nil
+	}
+	if result := compareUint(first.dev, second.dev); result != 0 {
+		return result, nil
+	}
+
+	return comparePEP440Local(first.local, second.local), nil
+}
+
+// comparePEP440Local orders local version labels segment by segment, numbers after strings and a
+// longer label after its prefix. No label at all comes first.
+func comparePEP440Local(x []string, y []string) int {
+	for i := 0; i < len(x) && i < len(y); i++ {
+		xNumber, yNumber := isNumber(x[i]), isNumber(y[i])
+		switch {
+		case xNumber && yNumber:
+			first, _ := strconv.ParseUint(x[i], 10, 64)
+			second, _ := strconv.ParseUint(y[i], 10, 64)
+			if result := compareUint(first, second); result != 0 {
+				return result
+			}
+		case xNumber:
+			return 1
+		case yNumber:
+			return -1
+		default:
+			if result := strings.Compare(x[i], y[i]); result != 0 {
+				return result
+			}
+		}
+	}
+
+	return sign(len(x) - len(y))
+}
+
+func compareUint(x uint64, y uint64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+// boolOrder is +1 when only the first of two differing flags is set, -1 otherwise
+func boolOrder(first bool) int {
+	if first {
+		return 1
+	}
+	return -1
+}
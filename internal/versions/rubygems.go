@@ -0,0 +1,99 @@
+package versions
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+var (
+	rubyGemsPattern  = regexp.MustCompile(`^[0-9]+(?:\.[0-9a-zA-Z]+)*(?:-[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?$`)
+	rubyGemsSegments = regexp.MustCompile(`[0-9]+|[a-zA-Z]+`)
+)
+
+// rubyGemsSegment is either a number or, for pre-releases, a string
+type rubyGemsSegment struct {
+	number *big.Int
+	text   string
+}
+
+// parseRubyGems returns the canonical segments of a version as Gem::Version does: the release and
+// pre-release parts without their trailing zeros, 1.0.0 is 1 and 1.0.a is 1.a
+func parseRubyGems(version string) ([]rubyGemsSegment, error) {
+	version = strings.TrimSpace(version)
+	if !rubyGemsPattern.MatchString(version) {
+		return nil, fmt.Errorf("invalid RubyGems version %q", version)
+	}
+	// A hyphen starts a pre-release, 1.0-1 is 1.0.pre.1
+	version = strings.ReplaceAll(version, "-", ".pre.")
+
+	var release, prerelease []rubyGemsSegment
+	for _, part := range rubyGemsSegments.FindAllString(version, -1) {
+		segment := rubyGemsSegment{text: part}
+		if isDigit(part[0]) {
+			segment = rubyGemsSegment{number: new(big.Int)}
+			segment.number.SetString(part, 10)
+		}
+
+		if segment.number == nil || len(prerelease) != 0 {
+			prerelease = append(prerelease, segment)
+		} else {
+			release = append(release, segment)
+		}
+	}
+
+	trim := func(segments []rubyGemsSegment) []rubyGemsSegment {
+		for len(segments) != 0 && segments[len(segments)-1].isZero() {
+			segments = segments[:len(segments)-1]
+		}
+		return segments
+	}
+
+	return append(trim(release), trim(prerelease)...), nil
+}
+
+func (segment rubyGemsSegment) isZero() bool {
+	return segment.number != nil && segment.number.Sign() == 0
+}
+
+// compareRubyGems orders RubyGems versions like Gem::Version#<=>: segment by segment, missing ones
+// count as 0 and a string segment (a pre-release) comes before any number, 1.0.a < 1.0 < 1.0.1
+func compareRubyGems(a string, b string) (int, error) {
+	first, err := parseRubyGems(a)
+	if err != nil {
+		return 0, err
+	}
+	second, err := parseRubyGems(b)
+	if err != nil {
+		return 0, err
+	}
+
+	zero := rubyGemsSegment{number: new(big.Int)}
+	for i := 0; i < len(first) || i < len(second); i++ {
+		x, y := zero, zero
+		if i < len(first) {
+			x = first[i]
+		}
+		if i < len(second) {
+			y = second[i]
+		}
+
+		switch {
+		case x.number != nil && y.number != nil:
+			if result := x.number.Cmp(y.number); result != 0 {
+				return result, nil
+			}
+		case x.number != nil:
+			return 1, nil
+		case y.number != nil:
+			return -1, nil
+		default:
+			if result := strings.Compare(x.text, y.text); result != 0 {
+				return result, nil
+			}
+		}
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,250 @@
+package versions
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	types "khazande/internal/types"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+var goVersionPattern = regexp.MustCompile(`^(?:go)?(\d+)(?:\.(\d+))?(?:\.(\d+))?((?:rc|beta)\d+)?$`)
+
+// InRange reports whether the version satisfies a GitHub advisory range such as
+// ">= 1.0.0, < 1.2.3", ordering versions with the comparator of the ecosystem. Every comma
+// separated clause has to hold.
+func InRange(ecosystem types.Ecosystem, version string, versionRange string) (bool, error) {
+	comparator := ComparatorFor(ecosystem)
+	for _, clause := range strings.Split(versionRange, ",") {
+		operator, bound, err := splitClause(clause)
+		if err != nil {
+			return false, err
+		}
+
+		result, err := comparator.Compare(version, bound)
+		if err != nil {
+			return false, err
+		}
+
+		var satisfied bool
+		switch operator {
+		case "=":
+			satisfied = result == 0
+		case "!=":
+			satisfied = result != 0
+		case "<":
+			satisfied = result < 0
+		case "<=":
+			satisfied = result <= 0
+		case ">":
+			satisfied = result > 0
+		case ">=":
+			satisfied = result >= 0
+		}
+
+		if !satisfied {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// Compare returns -1, 0 or +1 depending on whether a is lower, equal or higher than b
+// according to the ordering rules of the ecosystem.
+func Compare(ecosystem types.Ecosystem, a string, b string) (int, error) {
+	return ComparatorFor(ecosystem).Compare(a, b)
+}
+
+func splitClause(clause string) (string, string, error) {
+	clause = strings.TrimSpace(clause)
+	for _, operator := range []string{"<=", ">=", "!=", "<", ">", "="} {
+		if strings.HasPrefix(clause, operator) {
+			bound := strings.TrimSpace(strings.TrimPrefix(clause, operator))
+			if bound == "" {
+				return "", "", fmt.Errorf("missing version in range clause %q", clause)
+			}
+			return operator, bound, nil
+		}
+	}
+
+	if clause == "" {
+		return "", "", fmt.Errorf("empty range clause")
+	}
+
+	// A bare version is an exact match
+	return "=", clause, nil
+}
+
+func compareSemver(a string, b string) (int, error) {
+	first, err := semver.NewVersion(a)
+	if err != nil {
+		return 0, err
+	}
+
+	second, err := semver.NewVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	return first.Compare(second), nil
+}
+
+// compareDotted orders versions made of an arbitrary number of numeric and textual parts, the
+// fallback of ecosystems without a comparator of their own. Textual parts that are not known
+// post-release markers are treated as pre-releases and sort before the release itself.
+func compareDotted(a string, b string) int {
+	first, second := tokenize(a), tokenize(b)
+
+	for i := 0; i < len(first) || i < len(second); i++ {
+		var x, y string
+		if i < len(first) {
+			x = first[i]
+		}
+		if i < len(second) {
+			y = second[i]
+		}
+
+		// Missing numeric parts count as zero, 1.0 and 1.0.0 are equal
+		if x == "" && isNumber(y) {
+			x = "0"
+		}
+		if y == "" && isNumber(x) {
+			y = "0"
+		}
+
+		if result := compareToken(x, y); result != 0 {
+			return result
+		}
+	}
+
+	return 0
+}
+
+func tokenize(version string) []string {
+	version = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(version)), "v")
+
+	var tokens []string
+	var current strings.Builder
+	lastIsDigit := false
+
+	flush := func() {
+		// Release markers carry no ordering information, 1.0-final is 1.0
+		switch current.String() {
+		case "final", "ga", "release":
+			current.Reset()
+		}
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range version {
+		switch {
+		case r == '.' || r == '-' || r == '_' || r == '+':
+			flush()
+		case unicode.IsDigit(r):
+			if !lastIsDigit {
+				flush()
+			}
+			current.WriteRune(r)
+			lastIsDigit = true
+		default:
+			if lastIsDigit {
+				flush()
+			}
+			current.WriteRune(r)
+			lastIsDigit = false
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// tokenRank places a token relative to the release: pre-release markers are negative,
+// a missing token is the release itself and numbers or post-release markers are positive.
+func tokenRank(token string) int {
+	switch {
+	case token == "":
+		return 0
+	case isNumber(token):
+		return 2
+	case token == "post" || token == "sp" || token == "patch":
+		return 1
+	default:
+		return -1
+	}
+}
+
+func compareToken(x string, y string) int {
+	rankX, rankY := tokenRank(x), tokenRank(y)
+	if rankX != rankY {
+		if rankX < rankY {
+			return -1
+		}
+		return 1
+	}
+
+	if isNumber(x) && isNumber(y) {
+		first, _ := strconv.ParseUint(x, 10, 64)
+		second, _ := strconv.ParseUint(y, 10, 64)
+		switch {
+		case first < second:
+			return -1
+		case first > second:
+			return 1
+		}
+		return 0
+	}
+
+	return strings.Compare(x, y)
+}
+
+func isNumber(token string) bool {
+	if token == "" {
+		return false
+	}
+	for _, r := range token {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// GoSemver converts a Go release such as go1.21.3, go1.21 or go1.22rc1 to the semantic version the
+// Go vulndb uses for the standard library and the toolchain, v1.21.3, v1.21.0 and v1.22.0-rc.1. The go
+// prefix is optional and experiments appended to the version (e.g. "go1.21.3 X:boringcrypto") are ignored.
+func GoSemver(goVersion string) (string, bool) {
+	fields := strings.Fields(goVersion)
+	if len(fields) == 0 {
+		return "", false
+	}
+
+	match := goVersionPattern.FindStringSubmatch(fields[0])
+	if match == nil {
+		return "", false
+	}
+
+	minor, patch := match[2], match[3]
+	if minor == "" {
+		minor = "0"
+	}
+	if patch == "" {
+		patch = "0"
+	}
+
+	version := fmt.Sprintf("v%s.%s.%s", match[1], minor, patch)
+	if prerelease := match[4]; prerelease != "" {
+		kind := strings.TrimRight(prerelease, "0123456789")
+		version += "-" + kind + "." + strings.TrimPrefix(prerelease, kind)
+	}
+
+	return version, true
+}
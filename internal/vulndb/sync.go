@@ -0,0 +1,182 @@
+package vulndb
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Sync replaces the database at target with the one of source, a directory or a tarball (.tar,
+// .tar.gz or .tgz) of the vuln.go.dev layout, possibly nested in a top directory. The new copy is
+// checked before it takes the place of the old one, which is kept when anything fails.
+func Sync(source string, target string) (*DBIndex, error) {
+	target = filepath.Clean(target)
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return nil, err
+	}
+
+	staging, err := os.MkdirTemp(filepath.Dir(target), "."+filepath.Base(target)+"-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(staging)
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		err = copyDir(source, staging)
+	} else {
+		err = extractTarball(source, staging)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := findRoot(staging)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a Go vulnerability database: %v", source, err)
+	}
+	if _, err := Open(root); err != nil {
+		return nil, fmt.Errorf("%s is not a Go vulnerability database: %v", source, err)
+	}
+	dbIndex, err := ReadDBIndex(root)
+	if err != nil {
+		return nil, err
+	}
+
+	// Swap the directories, the old database is only removed once the new one is in place
+	previous := staging + ".previous"
+	if _, err := os.Stat(target); err == nil {
+		if err := os.Rename(target, previous); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.Rename(root, target); err != nil {
+		os.Rename(previous, target)
+		return nil, err
+	}
+	os.RemoveAll(previous)
+
+	return dbIndex, nil
+}
+
+// findRoot returns the directory holding index/db.json
+func findRoot(dir string) (string, error) {
+	root := ""
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && d.Name() == "db.json" && filepath.Base(filepath.Dir(path)) == "index" {
+			root = filepath.Dir(filepath.Dir(path))
+			return fs.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if root == "" {
+		return "", fmt.Errorf("index/db.json not found")
+	}
+
+	return root, nil
+}
+
+func copyDir(source string, destination string) error {
+	return filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relative, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.MkdirAll(filepath.Join(destination, relative), 0o755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		return writeFile(filepath.Join(destination, relative), file)
+	})
+}
+
+func extractTarball(source string, destination string) error {
+	file, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(source, ".gz") || strings.HasSuffix(source, ".tgz") {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	archive := tar.NewReader(reader)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", source, err)
+		}
+
+		// Entries escaping the destination are skipped
+		name := filepath.Clean(header.Name)
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			continue
+		}
+		path := filepath.Join(destination, name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(path, archive); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeFile(path string, content io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
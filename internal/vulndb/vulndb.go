@@ -0,0 +1,205 @@
+package vulndb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	osvModule "khazande/internal/osv"
+	"khazande/internal/types"
+	envsModule "khazande/pkg/envs"
+
+	"go.uber.org/zap"
+)
+
+// Database is a local copy of the Go vulnerability database in the layout served by
+// https://vuln.go.dev: index/db.json, index/modules.json and an OSV entry per ID/<id>.json
+type Database struct {
+	Path     string
+	Modified time.Time
+	modules  map[string][]ModuleVuln
+
+	mutex   sync.Mutex
+	entries map[string]*osvModule.Entry
+}
+
+// DBIndex is index/db.json
+type DBIndex struct {
+	Modified time.Time `json:"modified"`
+}
+
+// ModuleIndex is an item of index/modules.json
+type ModuleIndex struct {
+	Path  string       `json:"path"`
+	Vulns []ModuleVuln `json:"vulns"`
+}
+
+type ModuleVuln struct {
+	ID       string    `json:"id"`
+	Modified time.Time `json:"modified"`
+	Fixed    string    `json:"fixed"`
+}
+
+// Open reads the indexes of the database, the entries are read when a module asks for them
+func Open(path string) (*Database, error) {
+	dbIndex, err := ReadDBIndex(path)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(filepath.Join(path, "index", "modules.json"))
+	if err != nil {
+		return nil, err
+	}
+	var modules []ModuleIndex
+	if err := json.Unmarshal(content, &modules); err != nil {
+		return nil, fmt.Errorf("failed to parse index/modules.json: %v", err)
+	}
+
+	db := &Database{
+		Path:     path,
+		Modified: dbIndex.Modified,
+		modules:  make(map[string][]ModuleVuln, len(modules)),
+		entries:  make(map[string]*osvModule.Entry),
+	}
+	for _, module := range modules {
+		db.modules[module.Path] = module.Vulns
+	}
+
+	return db, nil
+}
+
+// ReadDBIndex reads index/db.json, it tells when the database was last modified
+func ReadDBIndex(path string) (*DBIndex, error) {
+	content, err := os.ReadFile(filepath.Join(path, "index", "db.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var dbIndex DBIndex
+	if err := json.Unmarshal(content, &dbIndex); err != nil {
+		return nil, fmt.Errorf("failed to parse index/db.json: %v", err)
+	}
+
+	return &dbIndex, nil
+}
+
+// Entries returns the entries affecting the module, whatever its version. Withdrawn entries are left
+// out, like the OSV database does. Entries that can't be read don't hide the others, they are
+// returned along with an error naming them.
+func (db *Database) Entries(module string) ([]*osvModule.Entry, error) {
+	var entries []*osvModule.Entry
+	var errs []error
+	for _, vuln := range db.modules[module] {
+		entry, err := db.Entry(vuln.ID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if entry.Withdrawn != "" {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, errors.Join(errs...)
+}
+
+// Entry reads ID/<id>.json
+func (db *Database) Entry(id string) (*osvModule.Entry, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	if entry, ok := db.entries[id]; ok {
+		return entry, nil
+	}
+
+	content, err := os.ReadFile(filepath.Join(db.Path, "ID", id+".json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var entry osvModule.Entry
+	if err := json.Unmarshal(content, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse ID/%s.json: %v", id, err)
+	}
+	db.entries[id] = &entry
+
+	return &entry, nil
+}
+
+// Client answers Go module queries from the database of GO_VULNDB_PATH, reopening it whenever
+// vulndb-sync refreshed it
+type Client struct {
+	Logger *zap.Logger
+	Envs   *envsModule.Envs
+
+	mutex    sync.Mutex
+	database *Database
+}
+
+// Enabled reports whether a local database is configured
+func (client *Client) Enabled() bool {
+	return client != nil && client.Envs.GO_VULNDB_PATH != ""
+}
+
+// FetchVulnerabilities returns the vulnerabilities of the Go packages keyed by types.PackageKey, like
+// Advisor.FetchVulnerabilitiesFromGithub. Packages of other ecosystems are ignored.
+func (client *Client) FetchVulnerabilities(packages []types.Package) map[string][]*types.Vulnerability {
+	vulnerabilities := make(map[string][]*types.Vulnerability)
+
+	database, err := client.Database()
+	if err != nil {
+		client.Logger.Sugar().Errorf("Failed to open the Go vulnerability database: %v", err)
+		return vulnerabilities
+	}
+
+	for _, pkg := range packages {
+		if pkg.Ecosystem == "" {
+			pkg.Ecosystem = types.EcosystemGo
+		}
+		if pkg.Ecosystem != types.EcosystemGo {
+			continue
+		}
+
+		// The entries that could be read are still matched when others fail
+		entries, err := database.Entries(pkg.Name)
+		if err != nil {
+			client.Logger.Sugar().Errorf("Failed to read some vulnerabilities of %s: %v", pkg.Name, err)
+		}
+
+		for _, vulnerability := range osvModule.MatchEntries(entries, pkg, client.Logger) {
+			vulnerability.Sources = []string{types.SourceVulnDB}
+			key := types.PackageKey(pkg.Ecosystem, pkg.Name)
+			vulnerabilities[key] = append(vulnerabilities[key], vulnerability)
+		}
+	}
+
+	return vulnerabilities
+}
+
+// Database opens the database on first use and again once the modified time of index/db.json changes
+func (client *Client) Database() (*Database, error) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	dbIndex, err := ReadDBIndex(client.Envs.GO_VULNDB_PATH)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.database == nil || !dbIndex.Modified.Equal(client.database.Modified) {
+		database, err := Open(client.Envs.GO_VULNDB_PATH)
+		if err != nil {
+			return nil, err
+		}
+		client.database = database
+		client.Logger.Info(fmt.Sprintf("Loaded the Go vulnerability database of %s, modified %s", database.Path, database.Modified.Format(time.RFC3339)))
+	}
+
+	return client.database, nil
+}
@@ -10,6 +10,14 @@ type Envs struct {
 	REDIS_PORT                   string
 	GITHUB_ADVISORT_DATABASE_URL string
 	GITHUB_TOKEN                 string
+	GITHUB_ADVISORY_MAX_PAGES    string
+	NVD_API_URL                  string
+	NVD_API_KEY                  string
+	OSV_PATH                     string
+	OSV_API_URL                  string
+	GO_VULNDB_PATH               string
+	ALPINE_SECDB_PATH            string
+	DEBIAN_SECURITY_TRACKER_PATH string
 }
 
 func ReadEnvs() *Envs {
@@ -21,6 +29,14 @@ func ReadEnvs() *Envs {
 	envs.REDIS_PORT = os.Getenv("REDIS_PORT")
 	envs.GITHUB_ADVISORT_DATABASE_URL = os.Getenv("GITHUB_ADVISORT_DATABASE_URL")
 	envs.GITHUB_TOKEN = os.Getenv("GITHUB_TOKEN")
+	envs.GITHUB_ADVISORY_MAX_PAGES = os.Getenv("GITHUB_ADVISORY_MAX_PAGES")
+	envs.NVD_API_URL = os.Getenv("NVD_API_URL")
+	envs.NVD_API_KEY = os.Getenv("NVD_API_KEY")
+	envs.OSV_PATH = os.Getenv("OSV_PATH")
+	envs.OSV_API_URL = os.Getenv("OSV_API_URL")
+	envs.GO_VULNDB_PATH = os.Getenv("GO_VULNDB_PATH")
+	envs.ALPINE_SECDB_PATH = os.Getenv("ALPINE_SECDB_PATH")
+	envs.DEBIAN_SECURITY_TRACKER_PATH = os.Getenv("DEBIAN_SECURITY_TRACKER_PATH")
 
 	return &envs
 }
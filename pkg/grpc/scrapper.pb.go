@@ -9,6 +9,7 @@ package grpc
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -20,38 +21,672 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type Severity int32
+
+const (
+	Severity_SEVERITY_UNSPECIFIED Severity = 0
+	Severity_SEVERITY_LOW         Severity = 1
+	Severity_SEVERITY_MODERATE    Severity = 2
+	Severity_SEVERITY_HIGH        Severity = 3
+	Severity_SEVERITY_CRITICAL    Severity = 4
+)
+
+// Enum value maps for Severity.
+var (
+	Severity_name = map[int32]string{
+		0: "SEVERITY_UNSPECIFIED",
+		1: "SEVERITY_LOW",
+		2: "SEVERITY_MODERATE",
+		3: "SEVERITY_HIGH",
+		4: "SEVERITY_CRITICAL",
+	}
+	Severity_value = map[string]int32{
+		"SEVERITY_UNSPECIFIED": 0,
+		"SEVERITY_LOW":         1,
+		"SEVERITY_MODERATE":    2,
+		"SEVERITY_HIGH":        3,
+		"SEVERITY_CRITICAL":    4,
+	}
+)
+
+func (x Severity) Enum() *Severity {
+	p := new(Severity)
+	*p = x
+	return p
+}
+
+func (x Severity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Severity) Descriptor() protoreflect.EnumDescriptor {
+	return file_grpc_scrapper_proto_enumTypes[0].Descriptor()
+}
+
+func (Severity) Type() protoreflect.EnumType {
+	return &file_grpc_scrapper_proto_enumTypes[0]
+}
+
+func (x Severity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Severity.Descriptor instead.
+func (Severity) EnumDescriptor() ([]byte, []int) {
+	return file_grpc_scrapper_proto_rawDescGZIP(), []int{0}
+}
+
+type CPEMatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Criteria              string `protobuf:"bytes,1,opt,name=criteria,proto3" json:"criteria,omitempty"`
+	Vendor                string `protobuf:"bytes,2,opt,name=vendor,proto3" json:"vendor,omitempty"`
+	Product               string `protobuf:"bytes,3,opt,name=product,proto3" json:"product,omitempty"`
+	Version               string `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+	VersionStartIncluding string `protobuf:"bytes,5,opt,name=versionStartIncluding,proto3" json:"versionStartIncluding,omitempty"`
+	VersionStartExcluding string `protobuf:"bytes,6,opt,name=versionStartExcluding,proto3" json:"versionStartExcluding,omitempty"`
+	VersionEndIncluding   string `protobuf:"bytes,7,opt,name=versionEndIncluding,proto3" json:"versionEndIncluding,omitempty"`
+	VersionEndExcluding   string `protobuf:"bytes,8,opt,name=versionEndExcluding,proto3" json:"versionEndExcluding,omitempty"`
+	Vulnerable            bool   `protobuf:"varint,9,opt,name=vulnerable,proto3" json:"vulnerable,omitempty"`
+}
+
+func (x *CPEMatch) Reset() {
+	*x = CPEMatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpc_scrapper_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CPEMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CPEMatch) ProtoMessage() {}
+
+func (x *CPEMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_scrapper_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CPEMatch.ProtoReflect.Descriptor instead.
+func (*CPEMatch) Descriptor() ([]byte, []int) {
+	return file_grpc_scrapper_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CPEMatch) GetCriteria() string {
+	if x != nil {
+		return x.Criteria
+	}
+	return ""
+}
+
+func (x *CPEMatch) GetVendor() string {
+	if x != nil {
+		return x.Vendor
+	}
+	return ""
+}
+
+func (x *CPEMatch) GetProduct() string {
+	if x != nil {
+		return x.Product
+	}
+	return ""
+}
+
+func (x *CPEMatch) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *CPEMatch) GetVersionStartIncluding() string {
+	if x != nil {
+		return x.VersionStartIncluding
+	}
+	return ""
+}
+
+func (x *CPEMatch) GetVersionStartExcluding() string {
+	if x != nil {
+		return x.VersionStartExcluding
+	}
+	return ""
+}
+
+func (x *CPEMatch) GetVersionEndIncluding() string {
+	if x != nil {
+		return x.VersionEndIncluding
+	}
+	return ""
+}
+
+func (x *CPEMatch) GetVersionEndExcluding() string {
+	if x != nil {
+		return x.VersionEndExcluding
+	}
+	return ""
+}
+
+func (x *CPEMatch) GetVulnerable() bool {
+	if x != nil {
+		return x.Vulnerable
+	}
+	return false
+}
+
 type Vulnerability struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name               string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	CVEID              string   `protobuf:"bytes,2,opt,name=CVEID,proto3" json:"CVEID,omitempty"`
-	PublishedDate      string   `protobuf:"bytes,3,opt,name=publishedDate,proto3" json:"publishedDate,omitempty"`
-	LastModified       string   `protobuf:"bytes,4,opt,name=lastModified,proto3" json:"lastModified,omitempty"`
-	Description        string   `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
-	VulnerableVersions []string `protobuf:"bytes,6,rep,name=VulnerableVersions,proto3" json:"VulnerableVersions,omitempty"`
-	NVDScore           string   `protobuf:"bytes,7,opt,name=NVDScore,proto3" json:"NVDScore,omitempty"`
-	CNAScore           string   `protobuf:"bytes,8,opt,name=CNAScore,proto3" json:"CNAScore,omitempty"`
+	Name               string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	CVEID              string   `protobuf:"bytes,2,opt,name=CVEID,proto3" json:"CVEID,omitempty"`
+	Description        string   `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	VulnerableVersions []string `protobuf:"bytes,6,rep,name=VulnerableVersions,proto3" json:"VulnerableVersions,omitempty"`
+	Summary            string   `protobuf:"bytes,9,opt,name=summary,proto3" json:"summary,omitempty"`
+	Severity           Severity `protobuf:"varint,10,opt,name=severity,proto3,enum=grpc.Severity" json:"severity,omitempty"`
+	AffectedVersions   string   `protobuf:"bytes,11,opt,name=affectedVersions,proto3" json:"affectedVersions,omitempty"`
+	PatchedVersions    string   `protobuf:"bytes,12,opt,name=patchedVersions,proto3" json:"patchedVersions,omitempty"`
+	GHSAID             string   `protobuf:"bytes,13,opt,name=GHSAID,proto3" json:"GHSAID,omitempty"`
+	Ecosystem          string   `protobuf:"bytes,14,opt,name=ecosystem,proto3" json:"ecosystem,omitempty"`
+	// Version of the package the finding is about
+	Version       string                 `protobuf:"bytes,15,opt,name=version,proto3" json:"version,omitempty"`
+	Indirect      bool                   `protobuf:"varint,16,opt,name=indirect,proto3" json:"indirect,omitempty"`
+	References    []string               `protobuf:"bytes,17,rep,name=references,proto3" json:"references,omitempty"`
+	PublishedDate *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=publishedDate,proto3" json:"publishedDate,omitempty"`
+	LastModified  *timestamppb.Timestamp `protobuf:"bytes,19,opt,name=lastModified,proto3" json:"lastModified,omitempty"`
+	// CVSS base scores, 0 when the source has none
+	NVDScore  float64 `protobuf:"fixed64,20,opt,name=NVDScore,proto3" json:"NVDScore,omitempty"`
+	CNAScore  float64 `protobuf:"fixed64,21,opt,name=CNAScore,proto3" json:"CNAScore,omitempty"`
+	NVDVector string  `protobuf:"bytes,22,opt,name=NVDVector,proto3" json:"NVDVector,omitempty"`
+	CNAVector string  `protobuf:"bytes,23,opt,name=CNAVector,proto3" json:"CNAVector,omitempty"`
+	// Other identifiers of the vulnerability, e.g. the CVE and GHSA IDs of a GitHub advisory
+	Aliases    []string    `protobuf:"bytes,24,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	CpeMatches []*CPEMatch `protobuf:"bytes,25,rep,name=cpeMatches,proto3" json:"cpeMatches,omitempty"`
+	// "affected", or "unknown" when the version couldn't be checked against the advisory
+	Match string `protobuf:"bytes,26,opt,name=match,proto3" json:"match,omitempty"`
+	// Why the version couldn't be checked, set along with an unknown match
+	MatchReason string `protobuf:"bytes,27,opt,name=match_reason,json=matchReason,proto3" json:"match_reason,omitempty"`
+}
+
+func (x *Vulnerability) Reset() {
+	*x = Vulnerability{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpc_scrapper_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Vulnerability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vulnerability) ProtoMessage() {}
+
+func (x *Vulnerability) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_scrapper_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vulnerability.ProtoReflect.Descriptor instead.
+func (*Vulnerability) Descriptor() ([]byte, []int) {
+	return file_grpc_scrapper_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Vulnerability) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetCVEID() string {
+	if x != nil {
+		return x.CVEID
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetVulnerableVersions() []string {
+	if x != nil {
+		return x.VulnerableVersions
+	}
+	return nil
+}
+
+func (x *Vulnerability) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
+}
+
+func (x *Vulnerability) GetAffectedVersions() string {
+	if x != nil {
+		return x.AffectedVersions
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetPatchedVersions() string {
+	if x != nil {
+		return x.PatchedVersions
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetGHSAID() string {
+	if x != nil {
+		return x.GHSAID
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetEcosystem() string {
+	if x != nil {
+		return x.Ecosystem
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetIndirect() bool {
+	if x != nil {
+		return x.Indirect
+	}
+	return false
+}
+
+func (x *Vulnerability) GetReferences() []string {
+	if x != nil {
+		return x.References
+	}
+	return nil
+}
+
+func (x *Vulnerability) GetPublishedDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublishedDate
+	}
+	return nil
+}
+
+func (x *Vulnerability) GetLastModified() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastModified
+	}
+	return nil
+}
+
+func (x *Vulnerability) GetNVDScore() float64 {
+	if x != nil {
+		return x.NVDScore
+	}
+	return 0
+}
+
+func (x *Vulnerability) GetCNAScore() float64 {
+	if x != nil {
+		return x.CNAScore
+	}
+	return 0
+}
+
+func (x *Vulnerability) GetNVDVector() string {
+	if x != nil {
+		return x.NVDVector
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetCNAVector() string {
+	if x != nil {
+		return x.CNAVector
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+func (x *Vulnerability) GetCpeMatches() []*CPEMatch {
+	if x != nil {
+		return x.CpeMatches
+	}
+	return nil
+}
+
+func (x *Vulnerability) GetMatch() string {
+	if x != nil {
+		return x.Match
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetMatchReason() string {
+	if x != nil {
+		return x.MatchReason
+	}
+	return ""
+}
+
+type VulnerabilityRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// When set, only the CVEs whose CPE ranges include this version are returned
+	Version string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	// Ecosystem of the package (go, npm, pypi, ...), it decides how versions are compared
+	Ecosystem string `protobuf:"bytes,3,opt,name=ecosystem,proto3" json:"ecosystem,omitempty"`
+	// When set, only the CPEs of this vendor are considered
+	Vendor string `protobuf:"bytes,4,opt,name=vendor,proto3" json:"vendor,omitempty"`
+}
+
+func (x *VulnerabilityRequest) Reset() {
+	*x = VulnerabilityRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpc_scrapper_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VulnerabilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VulnerabilityRequest) ProtoMessage() {}
+
+func (x *VulnerabilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_scrapper_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VulnerabilityRequest.ProtoReflect.Descriptor instead.
+func (*VulnerabilityRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_scrapper_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *VulnerabilityRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *VulnerabilityRequest) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *VulnerabilityRequest) GetEcosystem() string {
+	if x != nil {
+		return x.Ecosystem
+	}
+	return ""
+}
+
+func (x *VulnerabilityRequest) GetVendor() string {
+	if x != nil {
+		return x.Vendor
+	}
+	return ""
+}
+
+type VulnerabilityResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Vulnerabilities []*Vulnerability `protobuf:"bytes,1,rep,name=vulnerabilities,proto3" json:"vulnerabilities,omitempty"`
+}
+
+func (x *VulnerabilityResponse) Reset() {
+	*x = VulnerabilityResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpc_scrapper_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VulnerabilityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VulnerabilityResponse) ProtoMessage() {}
+
+func (x *VulnerabilityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_scrapper_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VulnerabilityResponse.ProtoReflect.Descriptor instead.
+func (*VulnerabilityResponse) Descriptor() ([]byte, []int) {
+	return file_grpc_scrapper_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *VulnerabilityResponse) GetVulnerabilities() []*Vulnerability {
+	if x != nil {
+		return x.Vulnerabilities
+	}
+	return nil
+}
+
+type VulnerabilitiesByIDRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// CVE IDs, e.g. CVE-2023-39325
+	Ids []string `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+}
+
+func (x *VulnerabilitiesByIDRequest) Reset() {
+	*x = VulnerabilitiesByIDRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpc_scrapper_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VulnerabilitiesByIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VulnerabilitiesByIDRequest) ProtoMessage() {}
+
+func (x *VulnerabilitiesByIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_scrapper_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VulnerabilitiesByIDRequest.ProtoReflect.Descriptor instead.
+func (*VulnerabilitiesByIDRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_scrapper_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *VulnerabilitiesByIDRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type Progress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Result pages of the search that have been fetched so far
+	PagesDiscovered int32 `protobuf:"varint,1,opt,name=pagesDiscovered,proto3" json:"pagesDiscovered,omitempty"`
+	// Vulnerabilities whose details have been extracted
+	DetailsDone int32 `protobuf:"varint,2,opt,name=detailsDone,proto3" json:"detailsDone,omitempty"`
+	// Vulnerabilities found by the search so far
+	DetailsTotal int32 `protobuf:"varint,3,opt,name=detailsTotal,proto3" json:"detailsTotal,omitempty"`
+	// Vulnerabilities whose details failed to load, they are counted in detailsDone as well
+	DetailsFailed int32 `protobuf:"varint,4,opt,name=detailsFailed,proto3" json:"detailsFailed,omitempty"`
+}
+
+func (x *Progress) Reset() {
+	*x = Progress{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpc_scrapper_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Progress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Progress) ProtoMessage() {}
+
+func (x *Progress) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_scrapper_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Progress.ProtoReflect.Descriptor instead.
+func (*Progress) Descriptor() ([]byte, []int) {
+	return file_grpc_scrapper_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Progress) GetPagesDiscovered() int32 {
+	if x != nil {
+		return x.PagesDiscovered
+	}
+	return 0
+}
+
+func (x *Progress) GetDetailsDone() int32 {
+	if x != nil {
+		return x.DetailsDone
+	}
+	return 0
+}
+
+func (x *Progress) GetDetailsTotal() int32 {
+	if x != nil {
+		return x.DetailsTotal
+	}
+	return 0
+}
+
+func (x *Progress) GetDetailsFailed() int32 {
+	if x != nil {
+		return x.DetailsFailed
+	}
+	return 0
+}
+
+type VulnerabilityStreamResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Event:
+	//	*VulnerabilityStreamResponse_Vulnerability
+	//	*VulnerabilityStreamResponse_Progress
+	Event isVulnerabilityStreamResponse_Event `protobuf_oneof:"event"`
 }
 
-func (x *Vulnerability) Reset() {
-	*x = Vulnerability{}
+func (x *VulnerabilityStreamResponse) Reset() {
+	*x = VulnerabilityStreamResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_grpc_scrapper_proto_msgTypes[0]
+		mi := &file_grpc_scrapper_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Vulnerability) String() string {
+func (x *VulnerabilityStreamResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Vulnerability) ProtoMessage() {}
+func (*VulnerabilityStreamResponse) ProtoMessage() {}
 
-func (x *Vulnerability) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_scrapper_proto_msgTypes[0]
+func (x *VulnerabilityStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_scrapper_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -62,92 +697,185 @@ func (x *Vulnerability) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Vulnerability.ProtoReflect.Descriptor instead.
-func (*Vulnerability) Descriptor() ([]byte, []int) {
-	return file_grpc_scrapper_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use VulnerabilityStreamResponse.ProtoReflect.Descriptor instead.
+func (*VulnerabilityStreamResponse) Descriptor() ([]byte, []int) {
+	return file_grpc_scrapper_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *Vulnerability) GetName() string {
-	if x != nil {
-		return x.Name
+func (m *VulnerabilityStreamResponse) GetEvent() isVulnerabilityStreamResponse_Event {
+	if m != nil {
+		return m.Event
 	}
-	return ""
+	return nil
 }
 
-func (x *Vulnerability) GetCVEID() string {
-	if x != nil {
-		return x.CVEID
+func (x *VulnerabilityStreamResponse) GetVulnerability() *Vulnerability {
+	if x, ok := x.GetEvent().(*VulnerabilityStreamResponse_Vulnerability); ok {
+		return x.Vulnerability
 	}
-	return ""
+	return nil
+}
+
+func (x *VulnerabilityStreamResponse) GetProgress() *Progress {
+	if x, ok := x.GetEvent().(*VulnerabilityStreamResponse_Progress); ok {
+		return x.Progress
+	}
+	return nil
+}
+
+type isVulnerabilityStreamResponse_Event interface {
+	isVulnerabilityStreamResponse_Event()
 }
 
-func (x *Vulnerability) GetPublishedDate() string {
+type VulnerabilityStreamResponse_Vulnerability struct {
+	Vulnerability *Vulnerability `protobuf:"bytes,1,opt,name=vulnerability,proto3,oneof"`
+}
+
+type VulnerabilityStreamResponse_Progress struct {
+	Progress *Progress `protobuf:"bytes,2,opt,name=progress,proto3,oneof"`
+}
+
+func (*VulnerabilityStreamResponse_Vulnerability) isVulnerabilityStreamResponse_Event() {}
+
+func (*VulnerabilityStreamResponse_Progress) isVulnerabilityStreamResponse_Event() {}
+
+type Package struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	// Ecosystem of the package (go, npm, pypi, ...), go when empty
+	Ecosystem string `protobuf:"bytes,3,opt,name=ecosystem,proto3" json:"ecosystem,omitempty"`
+}
+
+func (x *Package) Reset() {
+	*x = Package{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpc_scrapper_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Package) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Package) ProtoMessage() {}
+
+func (x *Package) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_scrapper_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Package.ProtoReflect.Descriptor instead.
+func (*Package) Descriptor() ([]byte, []int) {
+	return file_grpc_scrapper_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Package) GetName() string {
 	if x != nil {
-		return x.PublishedDate
+		return x.Name
 	}
 	return ""
 }
 
-func (x *Vulnerability) GetLastModified() string {
+func (x *Package) GetVersion() string {
 	if x != nil {
-		return x.LastModified
+		return x.Version
 	}
 	return ""
 }
 
-func (x *Vulnerability) GetDescription() string {
+func (x *Package) GetEcosystem() string {
 	if x != nil {
-		return x.Description
+		return x.Ecosystem
 	}
 	return ""
 }
 
-func (x *Vulnerability) GetVulnerableVersions() []string {
-	if x != nil {
-		return x.VulnerableVersions
+type PackageVulnerabilitiesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Packages []*Package `protobuf:"bytes,1,rep,name=packages,proto3" json:"packages,omitempty"`
+}
+
+func (x *PackageVulnerabilitiesRequest) Reset() {
+	*x = PackageVulnerabilitiesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpc_scrapper_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return nil
 }
 
-func (x *Vulnerability) GetNVDScore() string {
-	if x != nil {
-		return x.NVDScore
+func (x *PackageVulnerabilitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PackageVulnerabilitiesRequest) ProtoMessage() {}
+
+func (x *PackageVulnerabilitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_scrapper_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PackageVulnerabilitiesRequest.ProtoReflect.Descriptor instead.
+func (*PackageVulnerabilitiesRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_scrapper_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *Vulnerability) GetCNAScore() string {
+func (x *PackageVulnerabilitiesRequest) GetPackages() []*Package {
 	if x != nil {
-		return x.CNAScore
+		return x.Packages
 	}
-	return ""
+	return nil
 }
 
-type VulnerabilityRequest struct {
+type PackageFindings struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Package         *Package         `protobuf:"bytes,1,opt,name=package,proto3" json:"package,omitempty"`
+	Vulnerabilities []*Vulnerability `protobuf:"bytes,2,rep,name=vulnerabilities,proto3" json:"vulnerabilities,omitempty"`
 }
 
-func (x *VulnerabilityRequest) Reset() {
-	*x = VulnerabilityRequest{}
+func (x *PackageFindings) Reset() {
+	*x = PackageFindings{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_grpc_scrapper_proto_msgTypes[1]
+		mi := &file_grpc_scrapper_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *VulnerabilityRequest) String() string {
+func (x *PackageFindings) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*VulnerabilityRequest) ProtoMessage() {}
+func (*PackageFindings) ProtoMessage() {}
 
-func (x *VulnerabilityRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_scrapper_proto_msgTypes[1]
+func (x *PackageFindings) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_scrapper_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -158,43 +886,50 @@ func (x *VulnerabilityRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use VulnerabilityRequest.ProtoReflect.Descriptor instead.
-func (*VulnerabilityRequest) Descriptor() ([]byte, []int) {
-	return file_grpc_scrapper_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use PackageFindings.ProtoReflect.Descriptor instead.
+func (*PackageFindings) Descriptor() ([]byte, []int) {
+	return file_grpc_scrapper_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *VulnerabilityRequest) GetName() string {
+func (x *PackageFindings) GetPackage() *Package {
 	if x != nil {
-		return x.Name
+		return x.Package
 	}
-	return ""
+	return nil
 }
 
-type VulnerabilityResponse struct {
+func (x *PackageFindings) GetVulnerabilities() []*Vulnerability {
+	if x != nil {
+		return x.Vulnerabilities
+	}
+	return nil
+}
+
+type PackageVulnerabilitiesResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Vulnerabilities []*Vulnerability `protobuf:"bytes,1,rep,name=vulnerabilities,proto3" json:"vulnerabilities,omitempty"`
+	Findings []*PackageFindings `protobuf:"bytes,1,rep,name=findings,proto3" json:"findings,omitempty"`
 }
 
-func (x *VulnerabilityResponse) Reset() {
-	*x = VulnerabilityResponse{}
+func (x *PackageVulnerabilitiesResponse) Reset() {
+	*x = PackageVulnerabilitiesResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_grpc_scrapper_proto_msgTypes[2]
+		mi := &file_grpc_scrapper_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *VulnerabilityResponse) String() string {
+func (x *PackageVulnerabilitiesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*VulnerabilityResponse) ProtoMessage() {}
+func (*PackageVulnerabilitiesResponse) ProtoMessage() {}
 
-func (x *VulnerabilityResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_scrapper_proto_msgTypes[2]
+func (x *PackageVulnerabilitiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_scrapper_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -205,14 +940,14 @@ func (x *VulnerabilityResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use VulnerabilityResponse.ProtoReflect.Descriptor instead.
-func (*VulnerabilityResponse) Descriptor() ([]byte, []int) {
-	return file_grpc_scrapper_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use PackageVulnerabilitiesResponse.ProtoReflect.Descriptor instead.
+func (*PackageVulnerabilitiesResponse) Descriptor() ([]byte, []int) {
+	return file_grpc_scrapper_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *VulnerabilityResponse) GetVulnerabilities() []*Vulnerability {
+func (x *PackageVulnerabilitiesResponse) GetFindings() []*PackageFindings {
 	if x != nil {
-		return x.Vulnerabilities
+		return x.Findings
 	}
 	return nil
 }
@@ -221,38 +956,174 @@ var File_grpc_scrapper_proto protoreflect.FileDescriptor
 
 var file_grpc_scrapper_proto_rawDesc = []byte{
 	0x0a, 0x13, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x73, 0x63, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x67, 0x72, 0x70, 0x63, 0x22, 0x8d, 0x02, 0x0a, 0x0d,
-	0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x12, 0x0a,
-	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x12, 0x14, 0x0a, 0x05, 0x43, 0x56, 0x45, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x05, 0x43, 0x56, 0x45, 0x49, 0x44, 0x12, 0x24, 0x0a, 0x0d, 0x70, 0x75, 0x62, 0x6c, 0x69,
-	0x73, 0x68, 0x65, 0x64, 0x44, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d,
-	0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x64, 0x44, 0x61, 0x74, 0x65, 0x12, 0x22, 0x0a,
-	0x0c, 0x6c, 0x61, 0x73, 0x74, 0x4d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x65, 0x64, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x4d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x65,
-	0x64, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
-	0x69, 0x6f, 0x6e, 0x12, 0x2e, 0x0a, 0x12, 0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x6c,
-	0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52,
-	0x12, 0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x6c, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69,
-	0x6f, 0x6e, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x4e, 0x56, 0x44, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x18,
-	0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x4e, 0x56, 0x44, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12,
-	0x1a, 0x0a, 0x08, 0x43, 0x4e, 0x41, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x08, 0x43, 0x4e, 0x41, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x22, 0x2a, 0x0a, 0x14, 0x56,
-	0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x56, 0x0a, 0x15, 0x56, 0x75, 0x6c, 0x6e, 0x65,
-	0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x3d, 0x0a, 0x0f, 0x76, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74,
-	0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x67, 0x72, 0x70, 0x63,
-	0x2e, 0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x0f,
-	0x76, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x32,
-	0x64, 0x0a, 0x0f, 0x53, 0x63, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x12, 0x51, 0x0a, 0x14, 0x46, 0x65, 0x74, 0x63, 0x68, 0x56, 0x75, 0x6c, 0x6e, 0x65,
-	0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x1a, 0x2e, 0x67, 0x72, 0x70,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x67, 0x72, 0x70, 0x63, 0x1a, 0x1f, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xe2, 0x02, 0x0a,
+	0x08, 0x43, 0x50, 0x45, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x72, 0x69,
+	0x74, 0x65, 0x72, 0x69, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x72, 0x69,
+	0x74, 0x65, 0x72, 0x69, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x12, 0x18, 0x0a,
+	0x07, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x34, 0x0a, 0x15, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x72,
+	0x74, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x15, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x49, 0x6e,
+	0x63, 0x6c, 0x75, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x34, 0x0a, 0x15, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x45, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x69, 0x6e, 0x67,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x15, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x53,
+	0x74, 0x61, 0x72, 0x74, 0x45, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x30, 0x0a,
+	0x13, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x64, 0x49, 0x6e, 0x63, 0x6c, 0x75,
+	0x64, 0x69, 0x6e, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x64, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x69, 0x6e, 0x67, 0x12,
+	0x30, 0x0a, 0x13, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x64, 0x45, 0x78, 0x63,
+	0x6c, 0x75, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x64, 0x45, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x69, 0x6e,
+	0x67, 0x12, 0x1e, 0x0a, 0x0a, 0x76, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x6c, 0x65, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x76, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x6c,
+	0x65, 0x22, 0xc4, 0x06, 0x0a, 0x0d, 0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c,
+	0x69, 0x74, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x43, 0x56, 0x45, 0x49, 0x44,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x43, 0x56, 0x45, 0x49, 0x44, 0x12, 0x20, 0x0a,
+	0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x2e, 0x0a, 0x12, 0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x6c, 0x65, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x12, 0x56, 0x75, 0x6c,
+	0x6e, 0x65, 0x72, 0x61, 0x62, 0x6c, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12,
+	0x18, 0x0a, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x2a, 0x0a, 0x08, 0x73, 0x65, 0x76,
+	0x65, 0x72, 0x69, 0x74, 0x79, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x53, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x52, 0x08, 0x73, 0x65, 0x76,
+	0x65, 0x72, 0x69, 0x74, 0x79, 0x12, 0x2a, 0x0a, 0x10, 0x61, 0x66, 0x66, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x10, 0x61, 0x66, 0x66, 0x65, 0x63, 0x74, 0x65, 0x64, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x73, 0x12, 0x28, 0x0a, 0x0f, 0x70, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x61, 0x74, 0x63,
+	0x68, 0x65, 0x64, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x47,
+	0x48, 0x53, 0x41, 0x49, 0x44, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x47, 0x48, 0x53,
+	0x41, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x63, 0x6f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d,
+	0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x63, 0x6f, 0x73, 0x79, 0x73, 0x74, 0x65,
+	0x6d, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x69,
+	0x6e, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x18, 0x10, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69,
+	0x6e, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x66, 0x65, 0x72,
+	0x65, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x11, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x66,
+	0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x40, 0x0a, 0x0d, 0x70, 0x75, 0x62, 0x6c, 0x69,
+	0x73, 0x68, 0x65, 0x64, 0x44, 0x61, 0x74, 0x65, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0d, 0x70, 0x75, 0x62, 0x6c,
+	0x69, 0x73, 0x68, 0x65, 0x64, 0x44, 0x61, 0x74, 0x65, 0x12, 0x3e, 0x0a, 0x0c, 0x6c, 0x61, 0x73,
+	0x74, 0x4d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x65, 0x64, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0c, 0x6c, 0x61, 0x73,
+	0x74, 0x4d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x4e, 0x56, 0x44,
+	0x53, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x14, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x4e, 0x56, 0x44,
+	0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x43, 0x4e, 0x41, 0x53, 0x63, 0x6f, 0x72,
+	0x65, 0x18, 0x15, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x43, 0x4e, 0x41, 0x53, 0x63, 0x6f, 0x72,
+	0x65, 0x12, 0x1c, 0x0a, 0x09, 0x4e, 0x56, 0x44, 0x56, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x16,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x4e, 0x56, 0x44, 0x56, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12,
+	0x1c, 0x0a, 0x09, 0x43, 0x4e, 0x41, 0x56, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x17, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x43, 0x4e, 0x41, 0x56, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x18, 0x0a,
+	0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x18, 0x18, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
+	0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x12, 0x2e, 0x0a, 0x0a, 0x63, 0x70, 0x65, 0x4d, 0x61,
+	0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x19, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x43, 0x50, 0x45, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x0a, 0x63, 0x70, 0x65,
+	0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x61, 0x74, 0x63, 0x68,
+	0x18, 0x1a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x21, 0x0a,
+	0x0c, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x1b, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x4a, 0x04, 0x08, 0x03, 0x10, 0x04, 0x4a, 0x04, 0x08, 0x04, 0x10, 0x05, 0x4a, 0x04, 0x08, 0x07,
+	0x10, 0x08, 0x4a, 0x04, 0x08, 0x08, 0x10, 0x09, 0x22, 0x7a, 0x0a, 0x14, 0x56, 0x75, 0x6c, 0x6e,
+	0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1c,
+	0x0a, 0x09, 0x65, 0x63, 0x6f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x65, 0x63, 0x6f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x16, 0x0a, 0x06,
+	0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x76, 0x65,
+	0x6e, 0x64, 0x6f, 0x72, 0x22, 0x56, 0x0a, 0x15, 0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62,
+	0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a,
+	0x0f, 0x76, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x56, 0x75,
+	0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x0f, 0x76, 0x75, 0x6c,
+	0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0x2e, 0x0a, 0x1a,
+	0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x42,
+	0x79, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x69, 0x64,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x69, 0x64, 0x73, 0x22, 0xa0, 0x01, 0x0a,
+	0x08, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x28, 0x0a, 0x0f, 0x70, 0x61, 0x67,
+	0x65, 0x73, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0f, 0x70, 0x61, 0x67, 0x65, 0x73, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65,
+	0x72, 0x65, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x44, 0x6f,
+	0x6e, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c,
+	0x73, 0x44, 0x6f, 0x6e, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73,
+	0x54, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x64, 0x65, 0x74,
+	0x61, 0x69, 0x6c, 0x73, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x24, 0x0a, 0x0d, 0x64, 0x65, 0x74,
+	0x61, 0x69, 0x6c, 0x73, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0d, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x22,
+	0x91, 0x01, 0x0a, 0x1b, 0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x79, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x3b, 0x0a, 0x0d, 0x76, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x56, 0x75,
+	0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x48, 0x00, 0x52, 0x0d, 0x76,
+	0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x2c, 0x0a, 0x08,
+	0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x48, 0x00,
+	0x52, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x42, 0x07, 0x0a, 0x05, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x22, 0x55, 0x0a, 0x07, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09,
+	0x65, 0x63, 0x6f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x65, 0x63, 0x6f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x22, 0x4a, 0x0a, 0x1d, 0x50, 0x61,
+	0x63, 0x6b, 0x61, 0x67, 0x65, 0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69,
+	0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x08, 0x70,
+	0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x08, 0x70, 0x61,
+	0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x22, 0x79, 0x0a, 0x0f, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67,
+	0x65, 0x46, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x27, 0x0a, 0x07, 0x70, 0x61, 0x63,
+	0x6b, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x67, 0x72, 0x70,
+	0x63, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x61,
+	0x67, 0x65, 0x12, 0x3d, 0x0a, 0x0f, 0x76, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c,
+	0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79,
+	0x52, 0x0f, 0x76, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x22, 0x53, 0x0a, 0x1e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x56, 0x75, 0x6c, 0x6e,
+	0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x08, 0x66, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x63,
+	0x6b, 0x61, 0x67, 0x65, 0x46, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x08, 0x66, 0x69,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x2a, 0x77, 0x0a, 0x08, 0x53, 0x65, 0x76, 0x65, 0x72, 0x69,
+	0x74, 0x79, 0x12, 0x18, 0x0a, 0x14, 0x53, 0x45, 0x56, 0x45, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x55,
+	0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c,
+	0x53, 0x45, 0x56, 0x45, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x4c, 0x4f, 0x57, 0x10, 0x01, 0x12, 0x15,
+	0x0a, 0x11, 0x53, 0x45, 0x56, 0x45, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x52,
+	0x41, 0x54, 0x45, 0x10, 0x02, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x45, 0x56, 0x45, 0x52, 0x49, 0x54,
+	0x59, 0x5f, 0x48, 0x49, 0x47, 0x48, 0x10, 0x03, 0x12, 0x15, 0x0a, 0x11, 0x53, 0x45, 0x56, 0x45,
+	0x52, 0x49, 0x54, 0x59, 0x5f, 0x43, 0x52, 0x49, 0x54, 0x49, 0x43, 0x41, 0x4c, 0x10, 0x04, 0x32,
+	0x83, 0x03, 0x0a, 0x0f, 0x53, 0x63, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x12, 0x51, 0x0a, 0x14, 0x46, 0x65, 0x74, 0x63, 0x68, 0x56, 0x75, 0x6c, 0x6e,
+	0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x1a, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x56,
+	0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5a, 0x0a, 0x15, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12,
+	0x1a, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69,
+	0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79,
+	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x30, 0x01, 0x12, 0x55, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61,
+	0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x20, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x42,
+	0x79, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x67, 0x72, 0x70,
 	0x63, 0x2e, 0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x56, 0x75,
-	0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x6a, 0x0a, 0x1b, 0x46, 0x65, 0x74,
+	0x63, 0x68, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61,
+	0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x23, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x56, 0x75, 0x6c, 0x6e, 0x65, 0x72, 0x61, 0x62, 0x69,
+	0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x56, 0x75, 0x6c, 0x6e,
+	0x65, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
 	0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x08, 0x5a, 0x06, 0x2e, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x62,
 	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
@@ -269,21 +1140,48 @@ func file_grpc_scrapper_proto_rawDescGZIP() []byte {
 	return file_grpc_scrapper_proto_rawDescData
 }
 
-var file_grpc_scrapper_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_grpc_scrapper_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_grpc_scrapper_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_grpc_scrapper_proto_goTypes = []interface{}{
-	(*Vulnerability)(nil),         // 0: grpc.Vulnerability
-	(*VulnerabilityRequest)(nil),  // 1: grpc.VulnerabilityRequest
-	(*VulnerabilityResponse)(nil), // 2: grpc.VulnerabilityResponse
+	(Severity)(0),                          // 0: grpc.Severity
+	(*CPEMatch)(nil),                       // 1: grpc.CPEMatch
+	(*Vulnerability)(nil),                  // 2: grpc.Vulnerability
+	(*VulnerabilityRequest)(nil),           // 3: grpc.VulnerabilityRequest
+	(*VulnerabilityResponse)(nil),          // 4: grpc.VulnerabilityResponse
+	(*VulnerabilitiesByIDRequest)(nil),     // 5: grpc.VulnerabilitiesByIDRequest
+	(*Progress)(nil),                       // 6: grpc.Progress
+	(*VulnerabilityStreamResponse)(nil),    // 7: grpc.VulnerabilityStreamResponse
+	(*Package)(nil),                        // 8: grpc.Package
+	(*PackageVulnerabilitiesRequest)(nil),  // 9: grpc.PackageVulnerabilitiesRequest
+	(*PackageFindings)(nil),                // 10: grpc.PackageFindings
+	(*PackageVulnerabilitiesResponse)(nil), // 11: grpc.PackageVulnerabilitiesResponse
+	(*timestamppb.Timestamp)(nil),          // 12: google.protobuf.Timestamp
 }
 var file_grpc_scrapper_proto_depIdxs = []int32{
-	0, // 0: grpc.VulnerabilityResponse.vulnerabilities:type_name -> grpc.Vulnerability
-	1, // 1: grpc.ScrapperService.FetchVulnerabilities:input_type -> grpc.VulnerabilityRequest
-	2, // 2: grpc.ScrapperService.FetchVulnerabilities:output_type -> grpc.VulnerabilityResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	0,  // 0: grpc.Vulnerability.severity:type_name -> grpc.Severity
+	12, // 1: grpc.Vulnerability.publishedDate:type_name -> google.protobuf.Timestamp
+	12, // 2: grpc.Vulnerability.lastModified:type_name -> google.protobuf.Timestamp
+	1,  // 3: grpc.Vulnerability.cpeMatches:type_name -> grpc.CPEMatch
+	2,  // 4: grpc.VulnerabilityResponse.vulnerabilities:type_name -> grpc.Vulnerability
+	2,  // 5: grpc.VulnerabilityStreamResponse.vulnerability:type_name -> grpc.Vulnerability
+	6,  // 6: grpc.VulnerabilityStreamResponse.progress:type_name -> grpc.Progress
+	8,  // 7: grpc.PackageVulnerabilitiesRequest.packages:type_name -> grpc.Package
+	8,  // 8: grpc.PackageFindings.package:type_name -> grpc.Package
+	2,  // 9: grpc.PackageFindings.vulnerabilities:type_name -> grpc.Vulnerability
+	10, // 10: grpc.PackageVulnerabilitiesResponse.findings:type_name -> grpc.PackageFindings
+	3,  // 11: grpc.ScrapperService.FetchVulnerabilities:input_type -> grpc.VulnerabilityRequest
+	3,  // 12: grpc.ScrapperService.StreamVulnerabilities:input_type -> grpc.VulnerabilityRequest
+	5,  // 13: grpc.ScrapperService.GetVulnerabilities:input_type -> grpc.VulnerabilitiesByIDRequest
+	9,  // 14: grpc.ScrapperService.FetchPackageVulnerabilities:input_type -> grpc.PackageVulnerabilitiesRequest
+	4,  // 15: grpc.ScrapperService.FetchVulnerabilities:output_type -> grpc.VulnerabilityResponse
+	7,  // 16: grpc.ScrapperService.StreamVulnerabilities:output_type -> grpc.VulnerabilityStreamResponse
+	4,  // 17: grpc.ScrapperService.GetVulnerabilities:output_type -> grpc.VulnerabilityResponse
+	11, // 18: grpc.ScrapperService.FetchPackageVulnerabilities:output_type -> grpc.PackageVulnerabilitiesResponse
+	15, // [15:19] is the sub-list for method output_type
+	11, // [11:15] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_grpc_scrapper_proto_init() }
@@ -293,7 +1191,7 @@ func file_grpc_scrapper_proto_init() {
 	}
 	if !protoimpl.UnsafeEnabled {
 		file_grpc_scrapper_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Vulnerability); i {
+			switch v := v.(*CPEMatch); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -305,7 +1203,7 @@ func file_grpc_scrapper_proto_init() {
 			}
 		}
 		file_grpc_scrapper_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*VulnerabilityRequest); i {
+			switch v := v.(*Vulnerability); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -317,6 +1215,18 @@ func file_grpc_scrapper_proto_init() {
 			}
 		}
 		file_grpc_scrapper_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VulnerabilityRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpc_scrapper_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*VulnerabilityResponse); i {
 			case 0:
 				return &v.state
@@ -328,19 +1238,108 @@ func file_grpc_scrapper_proto_init() {
 				return nil
 			}
 		}
+		file_grpc_scrapper_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VulnerabilitiesByIDRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpc_scrapper_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Progress); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpc_scrapper_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VulnerabilityStreamResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpc_scrapper_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Package); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpc_scrapper_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PackageVulnerabilitiesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpc_scrapper_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PackageFindings); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpc_scrapper_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PackageVulnerabilitiesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_grpc_scrapper_proto_msgTypes[6].OneofWrappers = []interface{}{
+		(*VulnerabilityStreamResponse_Vulnerability)(nil),
+		(*VulnerabilityStreamResponse_Progress)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_grpc_scrapper_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   3,
+			NumEnums:      1,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_grpc_scrapper_proto_goTypes,
 		DependencyIndexes: file_grpc_scrapper_proto_depIdxs,
+		EnumInfos:         file_grpc_scrapper_proto_enumTypes,
 		MessageInfos:      file_grpc_scrapper_proto_msgTypes,
 	}.Build()
 	File_grpc_scrapper_proto = out.File
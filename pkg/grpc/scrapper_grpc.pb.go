@@ -23,6 +23,10 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ScrapperServiceClient interface {
 	FetchVulnerabilities(ctx context.Context, in *VulnerabilityRequest, opts ...grpc.CallOption) (*VulnerabilityResponse, error)
+	StreamVulnerabilities(ctx context.Context, in *VulnerabilityRequest, opts ...grpc.CallOption) (ScrapperService_StreamVulnerabilitiesClient, error)
+	GetVulnerabilities(ctx context.Context, in *VulnerabilitiesByIDRequest, opts ...grpc.CallOption) (*VulnerabilityResponse, error)
+	// Checks the packages against the GitHub Advisory Database
+	FetchPackageVulnerabilities(ctx context.Context, in *PackageVulnerabilitiesRequest, opts ...grpc.CallOption) (*PackageVulnerabilitiesResponse, error)
 }
 
 type scrapperServiceClient struct {
@@ -42,11 +46,65 @@ func (c *scrapperServiceClient) FetchVulnerabilities(ctx context.Context, in *Vu
 	return out, nil
 }
 
+func (c *scrapperServiceClient) StreamVulnerabilities(ctx context.Context, in *VulnerabilityRequest, opts ...grpc.CallOption) (ScrapperService_StreamVulnerabilitiesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ScrapperService_ServiceDesc.Streams[0], "/grpc.ScrapperService/StreamVulnerabilities", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &scrapperServiceStreamVulnerabilitiesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ScrapperService_StreamVulnerabilitiesClient interface {
+	Recv() (*VulnerabilityStreamResponse, error)
+	grpc.ClientStream
+}
+
+type scrapperServiceStreamVulnerabilitiesClient struct {
+	grpc.ClientStream
+}
+
+func (x *scrapperServiceStreamVulnerabilitiesClient) Recv() (*VulnerabilityStreamResponse, error) {
+	m := new(VulnerabilityStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *scrapperServiceClient) GetVulnerabilities(ctx context.Context, in *VulnerabilitiesByIDRequest, opts ...grpc.CallOption) (*VulnerabilityResponse, error) {
+	out := new(VulnerabilityResponse)
+	err := c.cc.Invoke(ctx, "/grpc.ScrapperService/GetVulnerabilities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scrapperServiceClient) FetchPackageVulnerabilities(ctx context.Context, in *PackageVulnerabilitiesRequest, opts ...grpc.CallOption) (*PackageVulnerabilitiesResponse, error) {
+	out := new(PackageVulnerabilitiesResponse)
+	err := c.cc.Invoke(ctx, "/grpc.ScrapperService/FetchPackageVulnerabilities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ScrapperServiceServer is the server API for ScrapperService service.
 // All implementations must embed UnimplementedScrapperServiceServer
 // for forward compatibility
 type ScrapperServiceServer interface {
 	FetchVulnerabilities(context.Context, *VulnerabilityRequest) (*VulnerabilityResponse, error)
+	StreamVulnerabilities(*VulnerabilityRequest, ScrapperService_StreamVulnerabilitiesServer) error
+	GetVulnerabilities(context.Context, *VulnerabilitiesByIDRequest) (*VulnerabilityResponse, error)
+	// Checks the packages against the GitHub Advisory Database
+	FetchPackageVulnerabilities(context.Context, *PackageVulnerabilitiesRequest) (*PackageVulnerabilitiesResponse, error)
 	mustEmbedUnimplementedScrapperServiceServer()
 }
 
@@ -57,6 +115,15 @@ type UnimplementedScrapperServiceServer struct {
 func (UnimplementedScrapperServiceServer) FetchVulnerabilities(context.Context, *VulnerabilityRequest) (*VulnerabilityResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FetchVulnerabilities not implemented")
 }
+func (UnimplementedScrapperServiceServer) StreamVulnerabilities(*VulnerabilityRequest, ScrapperService_StreamVulnerabilitiesServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamVulnerabilities not implemented")
+}
+func (UnimplementedScrapperServiceServer) GetVulnerabilities(context.Context, *VulnerabilitiesByIDRequest) (*VulnerabilityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVulnerabilities not implemented")
+}
+func (UnimplementedScrapperServiceServer) FetchPackageVulnerabilities(context.Context, *PackageVulnerabilitiesRequest) (*PackageVulnerabilitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchPackageVulnerabilities not implemented")
+}
 func (UnimplementedScrapperServiceServer) mustEmbedUnimplementedScrapperServiceServer() {}
 
 // UnsafeScrapperServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -88,6 +155,63 @@ func _ScrapperService_FetchVulnerabilities_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ScrapperService_StreamVulnerabilities_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(VulnerabilityRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ScrapperServiceServer).StreamVulnerabilities(m, &scrapperServiceStreamVulnerabilitiesServer{stream})
+}
+
+type ScrapperService_StreamVulnerabilitiesServer interface {
+	Send(*VulnerabilityStreamResponse) error
+	grpc.ServerStream
+}
+
+type scrapperServiceStreamVulnerabilitiesServer struct {
+	grpc.ServerStream
+}
+
+func (x *scrapperServiceStreamVulnerabilitiesServer) Send(m *VulnerabilityStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ScrapperService_GetVulnerabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VulnerabilitiesByIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScrapperServiceServer).GetVulnerabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.ScrapperService/GetVulnerabilities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScrapperServiceServer).GetVulnerabilities(ctx, req.(*VulnerabilitiesByIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScrapperService_FetchPackageVulnerabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PackageVulnerabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScrapperServiceServer).FetchPackageVulnerabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.ScrapperService/FetchPackageVulnerabilities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScrapperServiceServer).FetchPackageVulnerabilities(ctx, req.(*PackageVulnerabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ScrapperService_ServiceDesc is the grpc.ServiceDesc for ScrapperService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -99,7 +223,21 @@ var ScrapperService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FetchVulnerabilities",
 			Handler:    _ScrapperService_FetchVulnerabilities_Handler,
 		},
+		{
+			MethodName: "GetVulnerabilities",
+			Handler:    _ScrapperService_GetVulnerabilities_Handler,
+		},
+		{
+			MethodName: "FetchPackageVulnerabilities",
+			Handler:    _ScrapperService_FetchPackageVulnerabilities_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamVulnerabilities",
+			Handler:       _ScrapperService_StreamVulnerabilities_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "grpc/scrapper.proto",
 }